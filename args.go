@@ -1,10 +1,15 @@
 package pgsql
 
 import (
+	"errors"
+	"fmt"
+	"reflect"
 	"strconv"
 	"strings"
 )
 
+// argsCountSwitchToMap is the number of values after which a deduplicating Args switches from a linear scan to a map
+// to find existing values.
 const argsCountSwitchToMap = 32
 
 type Placeholder int32
@@ -15,9 +20,45 @@ func (p Placeholder) String() string {
 
 type Args struct {
 	values []interface{}
+
+	deduplicate bool
+	index       map[interface{}]Placeholder
+
+	strictIdentifiers bool
+
+	err error
+}
+
+// ArgsOption configures an Args. It may be passed to NewArgs or Build.
+type ArgsOption func(*Args)
+
+// Deduplicate causes Use to return the existing placeholder when a value is used more than once. Only comparable values
+// are deduplicated. Values such as slices and maps always use a new placeholder.
+//
+// PostgreSQL infers a single type for each parameter. If the same value is used in contexts that need different types,
+// such as an int compared to both an int column and a text column, the deduplicated placeholder can cause an error
+// such as "inconsistent types deduced for parameter". The same query works without Deduplicate.
+func Deduplicate() ArgsOption {
+	return func(a *Args) {
+		a.deduplicate = true
+	}
+}
+
+func NewArgs(options ...ArgsOption) *Args {
+	a := &Args{}
+	for _, o := range options {
+		o(a)
+	}
+	return a
 }
 
 func (a *Args) Use(v interface{}) Placeholder {
+	if a.deduplicate && isComparable(reflect.ValueOf(v)) {
+		if p, ok := a.find(v); ok {
+			return p
+		}
+	}
+
 	if len(a.values) == 0 {
 		a.values = make([]interface{}, 0, 8)
 	}
@@ -25,36 +66,178 @@ func (a *Args) Use(v interface{}) Placeholder {
 	a.values = append(a.values, v)
 	p := Placeholder(len(a.values))
 
+	if a.deduplicate {
+		if a.index != nil {
+			if isComparable(reflect.ValueOf(v)) {
+				a.index[v] = p
+			}
+		} else if len(a.values) > argsCountSwitchToMap {
+			a.buildIndex()
+		}
+	}
+
 	return p
 }
 
+// find returns the placeholder of an existing value equal to v. v must be comparable.
+func (a *Args) find(v interface{}) (Placeholder, bool) {
+	if a.index != nil {
+		p, ok := a.index[v]
+		return p, ok
+	}
+
+	for i, existing := range a.values {
+		// An incomparable existing value is never equal to a comparable v. Either the dynamic types differ or v would
+		// also be incomparable.
+		if existing == v {
+			return Placeholder(i + 1), true
+		}
+	}
+
+	return 0, false
+}
+
+func (a *Args) buildIndex() {
+	a.index = make(map[interface{}]Placeholder, len(a.values)*2)
+	for i, v := range a.values {
+		if isComparable(reflect.ValueOf(v)) {
+			if _, present := a.index[v]; !present {
+				a.index[v] = Placeholder(i + 1)
+			}
+		}
+	}
+}
+
+// isComparable reports whether v can be compared with == without panicking. Unlike reflect.Type.Comparable it inspects
+// the dynamic values of interface fields.
+func isComparable(v reflect.Value) bool {
+	if !v.IsValid() {
+		return true
+	}
+
+	switch v.Kind() {
+	case reflect.Slice, reflect.Map, reflect.Func:
+		return false
+	case reflect.Interface:
+		return isComparable(v.Elem())
+	case reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			if !isComparable(v.Index(i)) {
+				return false
+			}
+		}
+		return v.Type().Comparable()
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if !isComparable(v.Field(i)) {
+				return false
+			}
+		}
+		return true
+	default:
+		return true
+	}
+}
+
 func (a *Args) Values() []interface{} {
 	return a.values
 }
 
+// SetError records an error that occurred while writing SQL. Only the first error is kept. SQLWriter implementations
+// should call SetError rather than panic when they cannot produce valid SQL.
+func (a *Args) SetError(err error) {
+	if a.err == nil {
+		a.err = err
+	}
+}
+
+// Err returns the first error recorded with SetError.
+func (a *Args) Err() error {
+	return a.err
+}
+
+// Format returns s with each ? replaced by a placeholder for the corresponding value. ? inside string literals, quoted
+// identifiers, dollar quoted strings and comments is ignored. ?? is written as a literal ? so operators such as the
+// jsonb ?, ?| and ?& can be written as ??, ??| and ??&. A value that is a SQLWriter such as a *SelectStatement is
+// written inline instead of as a placeholder. See Sub. If the only value is a NamedArgs or pgx.NamedArgs named markers
+// are replaced instead. ?? is still written as ? but a single ? has no special meaning. See NamedArgs.
 func (a *Args) Format(s string, values ...interface{}) string {
+	if na, ok := asNamedArgs(values); ok {
+		return a.formatNamed(s, na)
+	}
+
+	original := s
 	b := &strings.Builder{}
 
-	for i := 0; ; i++ {
-		pos := strings.IndexByte(s, '?')
+	used := 0
+	for {
+		pos := indexMarker(s, "?")
 		if pos == -1 {
 			b.WriteString(s)
 			break
 		}
 
+		if pos+1 < len(s) && s[pos+1] == '?' {
+			b.WriteString(s[:pos+1])
+			s = s[pos+2:]
+			continue
+		}
+
+		if used >= len(values) {
+			a.SetError(fmt.Errorf("pgsql: %q has more placeholders than the %d args provided", original, len(values)))
+			b.WriteString(s)
+			break
+		}
+
 		b.WriteString(s[0:pos])
-		b.WriteString(a.Use(values[i]).String())
+		a.writeArg(b, values[used])
+		used++
 		s = s[pos+1:]
 	}
 
+	if used < len(values) {
+		a.SetError(fmt.Errorf("pgsql: %q has %d placeholders but %d args were provided", original, used, len(values)))
+	}
+
 	return b.String()
 }
 
+// writeArg writes v where a ? placeholder was in a format string. A SQLWriter is written inline using the same args so
+// its placeholders continue the numbering. Statements are parenthesized. Any other value is written as a placeholder.
+func (a *Args) writeArg(sb *strings.Builder, v interface{}) {
+	w, ok := v.(SQLWriter)
+	if !ok {
+		sb.WriteString(a.Use(v).String())
+		return
+	}
+
+	if isNilSQLWriter(w) {
+		a.SetError(errors.New("pgsql: SQLWriter argument is nil"))
+		return
+	}
+
+	if isStatement(w) {
+		sb.WriteByte('(')
+		w.WriteSQL(sb, a)
+		sb.WriteByte(')')
+		return
+	}
+
+	w.WriteSQL(sb, a)
+}
+
 func (a *Args) Clone() *Args {
-	b := &Args{}
+	b := &Args{deduplicate: a.deduplicate, strictIdentifiers: a.strictIdentifiers, err: a.err}
 
 	b.values = make([]interface{}, len(a.values))
 	copy(b.values, a.values)
 
+	if a.index != nil {
+		b.index = make(map[interface{}]Placeholder, len(a.index))
+		for k, v := range a.index {
+			b.index[k] = v
+		}
+	}
+
 	return b
 }
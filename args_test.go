@@ -86,6 +86,84 @@ func TestArgsClone(t *testing.T) {
 	assert.Len(t, c.Values(), 10004)
 }
 
+func TestArgsDeduplicate(t *testing.T) {
+	args := pgsql.NewArgs(pgsql.Deduplicate())
+
+	assert.Equal(t, pgsql.Placeholder(1), args.Use(42))
+	assert.Equal(t, pgsql.Placeholder(2), args.Use(7))
+	assert.Equal(t, pgsql.Placeholder(1), args.Use(42))
+	assert.Equal(t, pgsql.Placeholder(3), args.Use(int64(42)))
+	assert.Equal(t, pgsql.Placeholder(4), args.Use("42"))
+	assert.Equal(t, pgsql.Placeholder(4), args.Use("42"))
+	assert.Equal(t, []interface{}{42, 7, int64(42), "42"}, args.Values())
+
+	assert.Equal(t, "array[$5, $1, $2]", args.Format("array[?, ?, ?]", 1, 42, 7))
+}
+
+func TestArgsDeduplicateIncomparableValue(t *testing.T) {
+	args := pgsql.NewArgs(pgsql.Deduplicate())
+
+	type wrapper struct {
+		v interface{}
+	}
+
+	stringSlice := []string{"foo", "bar", "baz"}
+	assert.Equal(t, pgsql.Placeholder(1), args.Use(stringSlice))
+	assert.Equal(t, pgsql.Placeholder(2), args.Use(nil))
+	assert.Equal(t, pgsql.Placeholder(3), args.Use(wrapper{v: stringSlice}))
+	assert.Equal(t, pgsql.Placeholder(4), args.Use(wrapper{v: 1}))
+
+	// Incomparable values are always considered a new placeholder.
+	assert.Equal(t, pgsql.Placeholder(5), args.Use(stringSlice))
+	assert.Equal(t, pgsql.Placeholder(6), args.Use(wrapper{v: stringSlice}))
+
+	assert.Equal(t, pgsql.Placeholder(2), args.Use(nil))
+	assert.Equal(t, pgsql.Placeholder(4), args.Use(wrapper{v: 1}))
+	assert.Len(t, args.Values(), 6)
+}
+
+func TestArgsDeduplicateManyValues(t *testing.T) {
+	args := pgsql.NewArgs(pgsql.Deduplicate())
+	args.Use([]int{1})
+
+	for i := 0; i < 100; i++ {
+		assert.Equal(t, pgsql.Placeholder(i+2), args.Use(i))
+	}
+	args.Use([]int{2})
+
+	for i := 0; i < 100; i++ {
+		assert.Equal(t, pgsql.Placeholder(i+2), args.Use(i))
+	}
+	assert.Equal(t, pgsql.Placeholder(103), args.Use(100))
+	assert.Equal(t, pgsql.Placeholder(104), args.Use([]int{1}))
+	assert.Len(t, args.Values(), 104)
+}
+
+func TestArgsDeduplicateClone(t *testing.T) {
+	for _, n := range []int{3, 50} {
+		a := pgsql.NewArgs(pgsql.Deduplicate())
+		for i := 0; i < n; i++ {
+			a.Use(i)
+		}
+
+		b := a.Clone()
+		assert.Equal(t, pgsql.Placeholder(n+1), a.Use("a"))
+		assert.Equal(t, pgsql.Placeholder(n+1), b.Use("b"))
+		assert.Equal(t, pgsql.Placeholder(n+2), a.Use("b"))
+		assert.Equal(t, pgsql.Placeholder(n+1), b.Use("b"))
+		assert.Equal(t, pgsql.Placeholder(1), b.Use(0))
+		assert.Len(t, a.Values(), n+2)
+		assert.Len(t, b.Values(), n+1)
+	}
+}
+
+func TestBuildDeduplicate(t *testing.T) {
+	a := pgsql.Select("*").From("t").Where("tenant_id = ?", 7).Where("parent_tenant_id = ? or owner_id = ?", 7, 8)
+	sql, args := pgsql.Build(a, pgsql.Deduplicate())
+	assert.Equal(t, "select * from t where (tenant_id = $1) and (parent_tenant_id = $1 or owner_id = $2)", sql)
+	assert.Equal(t, []interface{}{7, 8}, args)
+}
+
 func BenchmarkArgs_1_Uses_1_Values(b *testing.B) {
 	benchmarkArgs(b, 1, 1)
 }
@@ -171,6 +249,96 @@ func benchmarkArgs(b *testing.B, UseCount int, valueCount int) {
 			args.Use(preboxedValues[j%valueCount])
 		}
 
+		if len(args.Values()) != UseCount {
+			b.Fatalf("expected len(args.Values()) to be %d, got %d", UseCount, len(args.Values()))
+		}
+	}
+}
+
+func BenchmarkArgsDeduplicate_1_Uses_1_Values(b *testing.B) {
+	benchmarkArgsDeduplicate(b, 1, 1)
+}
+
+func BenchmarkArgsDeduplicate_5_Uses_5_Values(b *testing.B) {
+	benchmarkArgsDeduplicate(b, 5, 5)
+}
+
+func BenchmarkArgsDeduplicate_5_Uses_4_Values(b *testing.B) {
+	benchmarkArgsDeduplicate(b, 5, 4)
+}
+
+func BenchmarkArgsDeduplicate_10_Uses_10_Values(b *testing.B) {
+	benchmarkArgsDeduplicate(b, 10, 10)
+}
+
+func BenchmarkArgsDeduplicate_10_Uses_9_Values(b *testing.B) {
+	benchmarkArgsDeduplicate(b, 10, 9)
+}
+
+func BenchmarkArgsDeduplicate_10_Uses_5_Values(b *testing.B) {
+	benchmarkArgsDeduplicate(b, 10, 5)
+}
+
+func BenchmarkArgsDeduplicate_30_Uses_30_Values(b *testing.B) {
+	benchmarkArgsDeduplicate(b, 30, 30)
+}
+
+func BenchmarkArgsDeduplicate_30_Uses_27_Values(b *testing.B) {
+	benchmarkArgsDeduplicate(b, 30, 27)
+}
+
+func BenchmarkArgsDeduplicate_30_Uses_15_Values(b *testing.B) {
+	benchmarkArgsDeduplicate(b, 30, 15)
+}
+
+func BenchmarkArgsDeduplicate_100_Uses_100_Values(b *testing.B) {
+	benchmarkArgsDeduplicate(b, 100, 100)
+}
+
+func BenchmarkArgsDeduplicate_100_Uses_97_Values(b *testing.B) {
+	benchmarkArgsDeduplicate(b, 100, 97)
+}
+
+func BenchmarkArgsDeduplicate_100_Uses_50_Values(b *testing.B) {
+	benchmarkArgsDeduplicate(b, 100, 50)
+}
+
+func BenchmarkArgsDeduplicate_1000_Uses_1000_Values(b *testing.B) {
+	benchmarkArgsDeduplicate(b, 1000, 1000)
+}
+
+func BenchmarkArgsDeduplicate_1000_Uses_900_Values(b *testing.B) {
+	benchmarkArgsDeduplicate(b, 1000, 900)
+}
+
+func BenchmarkArgsDeduplicate_1000_Uses_500_Values(b *testing.B) {
+	benchmarkArgsDeduplicate(b, 1000, 500)
+}
+
+func BenchmarkArgsDeduplicate_50000_Uses_50000_Values(b *testing.B) {
+	benchmarkArgsDeduplicate(b, 50000, 50000)
+}
+
+func BenchmarkArgsDeduplicate_50000_Uses_45000_Values(b *testing.B) {
+	benchmarkArgsDeduplicate(b, 50000, 45000)
+}
+
+func BenchmarkArgsDeduplicate_50000_Uses_25000_Values(b *testing.B) {
+	benchmarkArgsDeduplicate(b, 50000, 25000)
+}
+
+func benchmarkArgsDeduplicate(b *testing.B, UseCount int, valueCount int) {
+	preboxedValues := make([]interface{}, valueCount)
+	for i := 0; i < valueCount; i++ {
+		preboxedValues[i] = i
+	}
+
+	for i := 0; i < b.N; i++ {
+		args := pgsql.NewArgs(pgsql.Deduplicate())
+		for j := 0; j < UseCount; j++ {
+			args.Use(preboxedValues[j%valueCount])
+		}
+
 		if len(args.Values()) != valueCount {
 			b.Fatalf("expected len(args.Values()) to be %d, got %d", valueCount, len(args.Values()))
 		}
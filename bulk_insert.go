@@ -0,0 +1,227 @@
+package pgsql
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// MaxBindParameters is the maximum number of bind parameters PostgreSQL allows in a single statement.
+const MaxBindParameters = 65535
+
+type BulkInsertStrategy int8
+
+const (
+	// BulkInsertChunked inserts rows with a values list. Rows are split into as many statements as needed to stay
+	// under the bind parameter limit.
+	BulkInsertChunked BulkInsertStrategy = iota
+
+	// BulkInsertUnnest inserts all rows with a single statement that passes one array parameter per column to unnest.
+	// It requires the type of every column. See ColumnTypes.
+	BulkInsertUnnest
+)
+
+// BulkInsertStatement builds the statements to insert many rows. It is created by BulkInsert.
+type BulkInsertStatement struct {
+	tableName   interface{}
+	rows        interface{}
+	strategy    BulkInsertStrategy
+	maxParams   int
+	columnTypes map[string]string
+}
+
+// BulkInsert returns a builder for inserting rows into tableName. tableName may be a string or an Ident. rows must be a
+// slice whose elements implement Insertable or are structs that can be used with Struct. All rows must have the same
+// columns.
+func BulkInsert(tableName, rows interface{}) *BulkInsertStatement {
+	return &BulkInsertStatement{tableName: tableName, rows: rows, maxParams: MaxBindParameters}
+}
+
+func (bis *BulkInsertStatement) Strategy(strategy BulkInsertStrategy) *BulkInsertStatement {
+	bis.strategy = strategy
+	return bis
+}
+
+// MaxParams sets the maximum number of bind parameters per statement for the BulkInsertChunked strategy. It defaults
+// to MaxBindParameters. Each value is assumed to use a single parameter.
+func (bis *BulkInsertStatement) MaxParams(n int) *BulkInsertStatement {
+	bis.maxParams = n
+	return bis
+}
+
+// ColumnTypes sets the PostgreSQL type of each column. e.g. map[string]string{"id": "int8", "name": "text"}. It is
+// required by the BulkInsertUnnest strategy.
+func (bis *BulkInsertStatement) ColumnTypes(columnTypes map[string]string) *BulkInsertStatement {
+	bis.columnTypes = columnTypes
+	return bis
+}
+
+// Statements returns the insert statements. The statements may be further modified. e.g. with Returning or OnConflict.
+// No statements are returned if there are no rows.
+func (bis *BulkInsertStatement) Statements() ([]*InsertStatement, error) {
+	columns, rows, err := bis.insertData()
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, nil
+	}
+
+	switch bis.strategy {
+	case BulkInsertChunked:
+		return bis.chunkedStatements(columns, rows)
+	case BulkInsertUnnest:
+		is, err := bis.unnestStatement(columns, rows)
+		if err != nil {
+			return nil, err
+		}
+		return []*InsertStatement{is}, nil
+	default:
+		return nil, fmt.Errorf("pgsql: unknown bulk insert strategy %d", bis.strategy)
+	}
+}
+
+// insertData returns the shared columns and the values of each row.
+func (bis *BulkInsertStatement) insertData() ([]string, [][]SQLWriter, error) {
+	rv := reflect.ValueOf(bis.rows)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return nil, nil, fmt.Errorf("pgsql: bulk insert rows must be a slice, got %T", bis.rows)
+	}
+
+	var columns []string
+	rows := make([][]SQLWriter, 0, rv.Len())
+	for i := 0; i < rv.Len(); i++ {
+		elem := rv.Index(i).Interface()
+		data, ok := elem.(Insertable)
+		if !ok {
+			sd := Struct(elem)
+			if sd.err != nil {
+				return nil, nil, sd.err
+			}
+			data = sd
+		}
+
+		rowColumns, vs := data.InsertData()
+		if vs == nil {
+			return nil, nil, fmt.Errorf("pgsql: bulk insert row %d has no values", i)
+		}
+		if i == 0 {
+			columns = rowColumns
+			if len(columns) == 0 {
+				return nil, nil, errors.New("pgsql: bulk insert rows have no columns")
+			}
+		} else if !equalStrings(columns, rowColumns) {
+			return nil, nil, fmt.Errorf("pgsql: bulk insert row %d has columns %v, expected %v", i, rowColumns, columns)
+		}
+
+		for _, row := range vs.rows {
+			if len(row) != len(columns) {
+				return nil, nil, fmt.Errorf("pgsql: bulk insert row %d has %d values for %d columns", i, len(row), len(columns))
+			}
+			rows = append(rows, row)
+		}
+	}
+
+	return columns, rows, nil
+}
+
+func (bis *BulkInsertStatement) chunkedStatements(columns []string, rows [][]SQLWriter) ([]*InsertStatement, error) {
+	rowsPerStatement := bis.maxParams / len(columns)
+	if rowsPerStatement == 0 {
+		return nil, fmt.Errorf("pgsql: bulk insert of %d columns exceeds max params of %d", len(columns), bis.maxParams)
+	}
+
+	var statements []*InsertStatement
+	for len(rows) > 0 {
+		n := rowsPerStatement
+		if n > len(rows) {
+			n = len(rows)
+		}
+
+		vs := &ValuesStatement{rows: rows[:n:n]}
+		statements = append(statements, Insert(bis.tableName).Columns(columns...).Values(vs))
+		rows = rows[n:]
+	}
+
+	return statements, nil
+}
+
+func (bis *BulkInsertStatement) unnestStatement(columns []string, rows [][]SQLWriter) (*InsertStatement, error) {
+	ue := &unnestExpr{arrays: make([]interface{}, len(columns)), types: make([]string, len(columns))}
+	for i, c := range columns {
+		typeName, ok := bis.columnTypes[c]
+		if !ok {
+			return nil, fmt.Errorf("pgsql: bulk insert unnest strategy requires a type for column %q", c)
+		}
+		ue.types[i] = typeName
+
+		values := make([]interface{}, len(rows))
+		for j, row := range rows {
+			p, ok := row[i].(*Param)
+			if !ok {
+				return nil, fmt.Errorf("pgsql: bulk insert unnest strategy requires plain values, row %d column %q is %T", j, c, row[i])
+			}
+			values[j] = p.Value
+		}
+		ue.arrays[i] = typedSlice(values)
+	}
+
+	ss := Select("*").From(ue)
+	return Insert(bis.tableName).Columns(columns...).Select(ss), nil
+}
+
+// unnestExpr writes an unnest call of arrays each cast to an array of the corresponding type. The type names are
+// written verbatim rather than through a format string.
+type unnestExpr struct {
+	arrays []interface{}
+	types  []string
+}
+
+func (ue *unnestExpr) WriteSQL(sb *strings.Builder, args *Args) {
+	sb.WriteString("unnest(")
+	for i, a := range ue.arrays {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		sb.WriteString(args.Use(a).String())
+		sb.WriteString("::")
+		sb.WriteString(ue.types[i])
+		sb.WriteString("[]")
+	}
+	sb.WriteByte(')')
+}
+
+// typedSlice returns values as a slice of their common type such as []int64 so it can be encoded as a PostgreSQL
+// array. If the values do not share a single type or any value is nil, values is returned unchanged.
+func typedSlice(values []interface{}) interface{} {
+	if len(values) == 0 || values[0] == nil {
+		return values
+	}
+
+	t := reflect.TypeOf(values[0])
+	for _, v := range values {
+		if v == nil || reflect.TypeOf(v) != t {
+			return values
+		}
+	}
+
+	slice := reflect.MakeSlice(reflect.SliceOf(t), len(values), len(values))
+	for i, v := range values {
+		slice.Index(i).Set(reflect.ValueOf(v))
+	}
+
+	return slice.Interface()
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,131 @@
+package pgsql_test
+
+import (
+	"testing"
+
+	"github.com/jackc/pgsql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type bulkPerson struct {
+	ID   int64  `db:"id,insertdefault"`
+	Name string `db:"name"`
+	Age  int32  `db:"age"`
+}
+
+func TestBulkInsertChunked(t *testing.T) {
+	rows := []bulkPerson{{Name: "Alice", Age: 30}, {Name: "Bob", Age: 32}, {Name: "Carol", Age: 34}}
+
+	statements, err := pgsql.BulkInsert("people", rows).Statements()
+	require.NoError(t, err)
+	require.Len(t, statements, 1)
+
+	sql, args := pgsql.Build(statements[0].Returning("id"))
+	assert.Equal(t, "insert into people (id, name, age) values (default,$1,$2), (default,$3,$4), (default,$5,$6) returning id", sql)
+	assert.Equal(t, []interface{}{"Alice", int32(30), "Bob", int32(32), "Carol", int32(34)}, args)
+
+	statements, err = pgsql.BulkInsert("people", rows).MaxParams(7).Statements()
+	require.NoError(t, err)
+	require.Len(t, statements, 2)
+
+	sql, args = pgsql.Build(statements[0])
+	assert.Equal(t, "insert into people (id, name, age) values (default,$1,$2), (default,$3,$4)", sql)
+	assert.Equal(t, []interface{}{"Alice", int32(30), "Bob", int32(32)}, args)
+
+	sql, args = pgsql.Build(statements[1])
+	assert.Equal(t, "insert into people (id, name, age) values (default,$1,$2)", sql)
+	assert.Equal(t, []interface{}{"Carol", int32(34)}, args)
+}
+
+func TestBulkInsertChunkedParameterLimit(t *testing.T) {
+	rows := make([]pgsql.RowMap, 40000)
+	for i := range rows {
+		rows[i] = pgsql.RowMap{"a": i, "b": i}
+	}
+
+	statements, err := pgsql.BulkInsert("t", rows).Statements()
+	require.NoError(t, err)
+	require.Len(t, statements, 2)
+
+	_, args := pgsql.Build(statements[0])
+	assert.Len(t, args, 65534)
+	_, args = pgsql.Build(statements[1])
+	assert.Len(t, args, 80000-65534)
+}
+
+func TestBulkInsertUnnest(t *testing.T) {
+	rows := []pgsql.Insertable{
+		pgsql.RowMap{"name": "Alice", "age": int32(30)},
+		pgsql.RowMap{"name": "Bob", "age": int32(32)},
+	}
+
+	statements, err := pgsql.BulkInsert("people", rows).
+		Strategy(pgsql.BulkInsertUnnest).
+		ColumnTypes(map[string]string{"name": "text", "age": "int4"}).
+		Statements()
+	require.NoError(t, err)
+	require.Len(t, statements, 1)
+
+	sql, args := pgsql.Build(statements[0])
+	assert.Equal(t, "insert into people (age, name) select * from unnest($1::int4[], $2::text[])", sql)
+	assert.Equal(t, []interface{}{[]int32{30, 32}, []string{"Alice", "Bob"}}, args)
+}
+
+func TestBulkInsertUnnestTypeNamesAreVerbatim(t *testing.T) {
+	rows := []pgsql.RowMap{{"a": 1}}
+	statements, err := pgsql.BulkInsert("t", rows).Strategy(pgsql.BulkInsertUnnest).ColumnTypes(map[string]string{"a": `"odd?type"`}).Statements()
+	require.NoError(t, err)
+
+	sql, args, err := pgsql.BuildE(statements[0])
+	require.NoError(t, err)
+	assert.Equal(t, `insert into t (a) select * from unnest($1::"odd?type"[])`, sql)
+	assert.Equal(t, []interface{}{[]int{1}}, args)
+}
+
+type nilValuesInsertable struct{}
+
+func (nilValuesInsertable) InsertData() ([]string, *pgsql.ValuesStatement) {
+	return []string{"a"}, nil
+}
+
+func TestBulkInsertUnnestMixedTypes(t *testing.T) {
+	rows := []pgsql.RowMap{{"a": 1}, {"a": nil}}
+	statements, err := pgsql.BulkInsert("t", rows).Strategy(pgsql.BulkInsertUnnest).ColumnTypes(map[string]string{"a": "int8"}).Statements()
+	require.NoError(t, err)
+
+	_, args := pgsql.Build(statements[0])
+	assert.Equal(t, []interface{}{[]interface{}{1, nil}}, args)
+}
+
+func TestBulkInsertNoRows(t *testing.T) {
+	statements, err := pgsql.BulkInsert("people", []bulkPerson{}).Statements()
+	require.NoError(t, err)
+	assert.Empty(t, statements)
+}
+
+func TestBulkInsertErrors(t *testing.T) {
+	_, err := pgsql.BulkInsert("people", 42).Statements()
+	assert.EqualError(t, err, "pgsql: bulk insert rows must be a slice, got int")
+
+	_, err = pgsql.BulkInsert("people", []int{1, 2}).Statements()
+	assert.EqualError(t, err, "pgsql: Struct requires a struct or pointer to a struct, got int")
+
+	_, err = pgsql.BulkInsert("people", []nilValuesInsertable{{}}).Statements()
+	assert.EqualError(t, err, "pgsql: bulk insert row 0 has no values")
+
+	_, err = pgsql.BulkInsert("people", []pgsql.RowMap{{"a": 1}, {"b": 2}}).Statements()
+	assert.EqualError(t, err, "pgsql: bulk insert row 1 has columns [b], expected [a]")
+
+	_, err = pgsql.BulkInsert("people", []pgsql.RowMap{{"a": 1, "b": 2}}).MaxParams(1).Statements()
+	assert.EqualError(t, err, "pgsql: bulk insert of 2 columns exceeds max params of 1")
+
+	_, err = pgsql.BulkInsert("people", []pgsql.RowMap{{"a": 1}}).Strategy(pgsql.BulkInsertUnnest).Statements()
+	assert.EqualError(t, err, `pgsql: bulk insert unnest strategy requires a type for column "a"`)
+
+	_, err = pgsql.BulkInsert("people", []bulkPerson{{Name: "Alice"}}).
+		Strategy(pgsql.BulkInsertUnnest).
+		ColumnTypes(map[string]string{"id": "int8", "name": "text", "age": "int4"}).
+		Statements()
+	assert.EqualError(t, err, `pgsql: bulk insert unnest strategy requires plain values, row 0 column "id" is pgsql.rawSQL`)
+}
@@ -0,0 +1,214 @@
+package pgsql
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// CompoundStatement combines the results of multiple queries with union, intersect and except. Operations are applied
+// left to right in the order they are added. Each operand is parenthesized so it may have its own order by, limit and
+// offset.
+type CompoundStatement struct {
+	operands []*compoundOperand
+
+	orderByList []SQLWriter
+
+	limit  int64
+	offset int64
+
+	frozen bool
+}
+
+type compoundOperand struct {
+	op        string
+	statement SQLWriter
+}
+
+func Union(statements ...SQLWriter) *CompoundStatement {
+	return (&CompoundStatement{}).combine("union", statements)
+}
+
+func UnionAll(statements ...SQLWriter) *CompoundStatement {
+	return (&CompoundStatement{}).combine("union all", statements)
+}
+
+func Intersect(statements ...SQLWriter) *CompoundStatement {
+	return (&CompoundStatement{}).combine("intersect", statements)
+}
+
+func IntersectAll(statements ...SQLWriter) *CompoundStatement {
+	return (&CompoundStatement{}).combine("intersect all", statements)
+}
+
+func Except(statements ...SQLWriter) *CompoundStatement {
+	return (&CompoundStatement{}).combine("except", statements)
+}
+
+func ExceptAll(statements ...SQLWriter) *CompoundStatement {
+	return (&CompoundStatement{}).combine("except all", statements)
+}
+
+func (cs *CompoundStatement) Union(statements ...SQLWriter) *CompoundStatement {
+	cs = cs.mutable()
+	return cs.combine("union", statements)
+}
+
+func (cs *CompoundStatement) UnionAll(statements ...SQLWriter) *CompoundStatement {
+	cs = cs.mutable()
+	return cs.combine("union all", statements)
+}
+
+func (cs *CompoundStatement) Intersect(statements ...SQLWriter) *CompoundStatement {
+	cs = cs.mutable()
+	return cs.combine("intersect", statements)
+}
+
+func (cs *CompoundStatement) IntersectAll(statements ...SQLWriter) *CompoundStatement {
+	cs = cs.mutable()
+	return cs.combine("intersect all", statements)
+}
+
+func (cs *CompoundStatement) Except(statements ...SQLWriter) *CompoundStatement {
+	cs = cs.mutable()
+	return cs.combine("except", statements)
+}
+
+func (cs *CompoundStatement) ExceptAll(statements ...SQLWriter) *CompoundStatement {
+	cs = cs.mutable()
+	return cs.combine("except all", statements)
+}
+
+func (cs *CompoundStatement) combine(op string, statements []SQLWriter) *CompoundStatement {
+	for _, s := range statements {
+		cs.operands = append(cs.operands, &compoundOperand{op: op, statement: s})
+	}
+	return cs
+}
+
+func (cs *CompoundStatement) Order(s string, args ...interface{}) *CompoundStatement {
+	cs = cs.mutable()
+	cs.orderByList = append(cs.orderByList, &FormatString{s: s, args: args})
+	return cs
+}
+
+func (cs *CompoundStatement) ReplaceOrder(s string, args ...interface{}) *CompoundStatement {
+	cs = cs.mutable()
+	cs.orderByList = []SQLWriter{&FormatString{s: s, args: args}}
+	return cs
+}
+
+func (cs *CompoundStatement) Limit(n int64) *CompoundStatement {
+	cs = cs.mutable()
+	cs.limit = n
+	return cs
+}
+
+func (cs *CompoundStatement) Offset(n int64) *CompoundStatement {
+	cs = cs.mutable()
+	cs.offset = n
+	return cs
+}
+
+// Clone returns a copy of cs that can be modified without affecting cs. Operand statements are copied too. The copy is
+// not frozen.
+func (cs *CompoundStatement) Clone() *CompoundStatement {
+	c := *cs
+	c.operands = make([]*compoundOperand, len(cs.operands))
+	for i, o := range cs.operands {
+		c.operands[i] = &compoundOperand{op: o.op, statement: cloneStatement(o.statement)}
+	}
+	c.orderByList = cloneSlice(cs.orderByList)
+	c.frozen = false
+	return &c
+}
+
+// Freeze makes cs copy-on-write. See SelectStatement.Freeze.
+func (cs *CompoundStatement) Freeze() *CompoundStatement {
+	cs.frozen = true
+	return cs
+}
+
+func (cs *CompoundStatement) mutable() *CompoundStatement {
+	if cs.frozen {
+		return cs.Clone()
+	}
+	return cs
+}
+
+// Count returns a select statement that counts the rows cs returns. The order by, limit and offset of cs are dropped.
+// cs is not modified.
+func (cs *CompoundStatement) Count() *SelectStatement {
+	counted := &CompoundStatement{operands: append([]*compoundOperand(nil), cs.operands...)}
+	return Select("count(*)").From(Sub(counted).As("counted"))
+}
+
+func (cs *CompoundStatement) WriteSQL(sb *strings.Builder, args *Args) {
+	if len(cs.operands) == 0 {
+		args.SetError(errors.New("pgsql: compound statement has no operands"))
+		return
+	}
+	for _, o := range cs.operands {
+		if isNilSQLWriter(o.statement) {
+			args.SetError(errors.New("pgsql: compound statement operand is nil"))
+			return
+		}
+	}
+	if cs.limit < 0 || cs.offset < 0 {
+		args.SetError(errors.New("pgsql: limit and offset must not be negative"))
+	}
+
+	// PostgreSQL gives intersect a higher precedence than union and except. To preserve left to right evaluation the
+	// preceding operands must be wrapped in parentheses when an intersect follows a union or except.
+	closeBefore := make([]bool, len(cs.operands))
+	openCount := 0
+	prefixHasLowPrecedence := false
+	for i := 1; i < len(cs.operands); i++ {
+		if strings.HasPrefix(cs.operands[i].op, "intersect") {
+			if prefixHasLowPrecedence {
+				closeBefore[i] = true
+				openCount++
+				prefixHasLowPrecedence = false
+			}
+		} else {
+			prefixHasLowPrecedence = true
+		}
+	}
+
+	for i := 0; i < openCount; i++ {
+		sb.WriteByte('(')
+	}
+
+	for i, o := range cs.operands {
+		if i > 0 {
+			if closeBefore[i] {
+				sb.WriteByte(')')
+			}
+			sb.WriteByte(' ')
+			sb.WriteString(o.op)
+			sb.WriteByte(' ')
+		}
+		sb.WriteByte('(')
+		o.statement.WriteSQL(sb, args)
+		sb.WriteByte(')')
+	}
+
+	if len(cs.orderByList) > 0 {
+		sb.WriteString(" order by ")
+		for i, e := range cs.orderByList {
+			if i > 0 {
+				sb.WriteString(", ")
+			}
+			e.WriteSQL(sb, args)
+		}
+	}
+
+	if cs.limit != 0 {
+		sb.WriteString(" limit ")
+		sb.WriteString(strconv.FormatInt(cs.limit, 10))
+	}
+	if cs.offset != 0 {
+		sb.WriteString(" offset ")
+		sb.WriteString(strconv.FormatInt(cs.offset, 10))
+	}
+}
@@ -0,0 +1,80 @@
+package pgsql_test
+
+import (
+	"testing"
+
+	"github.com/jackc/pgsql"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnion(t *testing.T) {
+	a := pgsql.Union(
+		pgsql.Select("name").From("people").Where("age > ?", 30),
+		pgsql.Select("name").From("robots").Where("model = ?", "T-800"),
+	)
+	sql, args := pgsql.Build(a)
+	assert.Equal(t, "(select name from people where (age > $1)) union (select name from robots where (model = $2))", sql)
+	assert.Equal(t, []interface{}{30, "T-800"}, args)
+}
+
+func TestCompoundStatementOperations(t *testing.T) {
+	a := pgsql.UnionAll(pgsql.Select("1"), pgsql.Values().Row(2)).
+		Except(pgsql.Select("3")).
+		ExceptAll(pgsql.Select("4")).
+		IntersectAll(pgsql.Select("5"))
+	sql, args := pgsql.Build(a)
+	assert.Equal(t, "((select 1) union all (values ($1)) except (select 3) except all (select 4)) intersect all (select 5)", sql)
+	assert.Equal(t, []interface{}{2}, args)
+
+	a = pgsql.Intersect(pgsql.Select("1"), pgsql.Select("2")).Union(pgsql.Select("3")).Intersect(pgsql.Select("4"))
+	sql, args = pgsql.Build(a)
+	assert.Equal(t, "((select 1) intersect (select 2) union (select 3)) intersect (select 4)", sql)
+	assert.Empty(t, args)
+
+	a = pgsql.Except(pgsql.Select("1"), pgsql.Select("2")).Intersect(pgsql.Select("3")).Union(pgsql.Select("4")).Intersect(pgsql.Select("5"))
+	sql, args = pgsql.Build(a)
+	assert.Equal(t, "(((select 1) except (select 2)) intersect (select 3) union (select 4)) intersect (select 5)", sql)
+	assert.Empty(t, args)
+}
+
+func TestCompoundStatementOrderLimitOffset(t *testing.T) {
+	a := pgsql.Union(
+		pgsql.Select("name").From("people").Order("name").Limit(10),
+		pgsql.Select("name").From("robots").Where("model = ?", "T-800"),
+	).Order("name desc").Limit(5).Offset(20)
+	sql, args := pgsql.Build(a)
+	assert.Equal(t, "(select name from people order by name limit 10) union (select name from robots where (model = $1)) order by name desc limit 5 offset 20", sql)
+	assert.Equal(t, []interface{}{"T-800"}, args)
+
+	a.ReplaceOrder("length(name) > ?", 3)
+	sql, args = pgsql.Build(a)
+	assert.Equal(t, "(select name from people order by name limit 10) union (select name from robots where (model = $1)) order by length(name) > $2 limit 5 offset 20", sql)
+	assert.Equal(t, []interface{}{"T-800", 3}, args)
+}
+
+func TestCompoundStatementInWith(t *testing.T) {
+	a := pgsql.Select("*").From("names").Where("name like ?", "A%")
+	a.With("names", pgsql.Union(pgsql.Select("name").From("people").Where("age > ?", 30), pgsql.Select("name").From("robots")))
+	sql, args := pgsql.Build(a)
+	assert.Equal(t, "with names as ((select name from people where (age > $1)) union (select name from robots)) select * from names where (name like $2)", sql)
+	assert.Equal(t, []interface{}{30, "A%"}, args)
+}
+
+func TestCompoundStatementCount(t *testing.T) {
+	cs := pgsql.Union(pgsql.Select("id").From("a").Where("x = ?", 1), pgsql.Select("id").From("b")).Order("id").Limit(10)
+
+	sql, args := pgsql.Build(cs.Count())
+	assert.Equal(t, "select count(*) from ((select id from a where (x = $1)) union (select id from b)) as counted", sql)
+	assert.Equal(t, []interface{}{1}, args)
+}
+
+func TestCompoundStatementCloneCopiesOperands(t *testing.T) {
+	a := pgsql.Select("id").From("a")
+	base := pgsql.Union(a, pgsql.Select("id").From("b"))
+	c := base.Clone().Limit(1)
+	a.Where("id > ?", 10)
+
+	sql, args := pgsql.Build(c)
+	assert.Equal(t, "(select id from a) union (select id from b) limit 1", sql)
+	assert.Empty(t, args)
+}
@@ -1,44 +1,225 @@
 package pgsql
 
 import (
+	"errors"
+	"fmt"
 	"strings"
 )
 
 type DeleteStatement struct {
+	withList      withList
+	only          bool
 	tableName     string
+	tableNameErr  error
+	alias         string
+	usingList     []SQLWriter
 	whereList     whereList
 	returningList returningList
+
+	frozen bool
 }
 
-func Delete(tableName string) *DeleteStatement {
-	return &DeleteStatement{tableName: tableName}
+// Delete returns a statement that deletes from tableName. tableName may be a string or an Ident. Use As to set an
+// alias.
+func Delete(tableName interface{}) *DeleteStatement {
+	name, err := nameOf("delete table name", tableName)
+	return &DeleteStatement{tableName: name, tableNameErr: err}
 }
 
 func (ds *DeleteStatement) DeleteStatement() (*DeleteStatement, error) {
 	return ds, nil
 }
 
+func (ds *DeleteStatement) With(name string, query SQLWriter) *DeleteStatement {
+	ds = ds.mutable()
+	ds.withList = append(ds.withList, &CommonTableExpression{Name: name, Query: query})
+	return ds
+}
+
+func (ds *DeleteStatement) WithRecursive(name string, query SQLWriter) *DeleteStatement {
+	ds = ds.mutable()
+	ds.withList = append(ds.withList, &CommonTableExpression{Name: name, Recursive: true, Query: query})
+	return ds
+}
+
+// WithCTE adds cte to the with clause. Use this instead of With when a column list or materialization is needed.
+func (ds *DeleteStatement) WithCTE(cte *CommonTableExpression) *DeleteStatement {
+	ds = ds.mutable()
+	ds.withList = append(ds.withList, cte)
+	return ds
+}
+
+// Only restricts the delete to the named table. Rows in tables that inherit from it are not deleted.
+func (ds *DeleteStatement) Only() *DeleteStatement {
+	ds = ds.mutable()
+	ds.only = true
+	return ds
+}
+
+// As sets an alias for the table.
+func (ds *DeleteStatement) As(alias string) *DeleteStatement {
+	ds = ds.mutable()
+	ds.alias = alias
+	return ds
+}
+
+// Using adds a table expression whose columns may be referenced in the where clause. Multiple calls add multiple
+// using items. item may be a format string with args or a SQLWriter such as Sub(stmt).As("t").
+func (ds *DeleteStatement) Using(item interface{}, args ...interface{}) *DeleteStatement {
+	ds = ds.mutable()
+	ds.usingList = append(ds.usingList, newFromItem(item, args))
+	return ds
+}
+
+// Where adds a condition to the where clause. Multiple conditions are combined with and.
 func (ds *DeleteStatement) Where(s string, args ...interface{}) *DeleteStatement {
+	ds = ds.mutable()
 	ds.whereList = append(ds.whereList, &FormatString{s: s, args: args})
 	return ds
 }
 
+// WhereExpr adds an expression such as Eq(Col("id"), 42) to the where clause.
+func (ds *DeleteStatement) WhereExpr(expr SQLWriter) *DeleteStatement {
+	ds = ds.mutable()
+	ds.whereList = append(ds.whereList, checkExpr("condition", expr))
+	return ds
+}
+
 func (ds *DeleteStatement) Returning(s string, args ...interface{}) *DeleteStatement {
+	ds = ds.mutable()
 	ds.returningList = append(ds.returningList, &FormatString{s: s, args: args})
 	return ds
 }
 
+// Clone returns a copy of ds that can be modified without affecting ds. Common table expressions and using items that
+// are statements or subqueries are copied too. Other SQLWriters are shared. The copy is not frozen.
+func (ds *DeleteStatement) Clone() *DeleteStatement {
+	c := *ds
+	c.withList = ds.withList.clone()
+	c.usingList = nil
+	for _, u := range ds.usingList {
+		c.usingList = append(c.usingList, cloneStatement(u))
+	}
+	c.whereList = cloneSlice(ds.whereList)
+	c.returningList = cloneSlice(ds.returningList)
+	c.frozen = false
+	return &c
+}
+
+// Freeze makes ds copy-on-write. See SelectStatement.Freeze.
+func (ds *DeleteStatement) Freeze() *DeleteStatement {
+	ds.frozen = true
+	return ds
+}
+
+func (ds *DeleteStatement) mutable() *DeleteStatement {
+	if ds.frozen {
+		return ds.Clone()
+	}
+	return ds
+}
+
 func (ds *DeleteStatement) WriteSQL(sb *strings.Builder, args *Args) {
+	if ds.tableNameErr != nil {
+		args.SetError(ds.tableNameErr)
+	} else if ds.tableName == "" {
+		args.SetError(errors.New("pgsql: delete table name is empty"))
+	}
+
+	ds.withList.WriteSQL(sb, args)
 	sb.WriteString("delete from ")
-	sb.WriteString(ds.tableName)
+	if ds.only {
+		sb.WriteString("only ")
+	}
+	writeName(sb, args, ds.tableName)
+	if ds.alias != "" {
+		sb.WriteString(" as ")
+		writeName(sb, args, ds.alias)
+	}
+
+	if len(ds.usingList) > 0 {
+		sb.WriteString(" using ")
+		for i, u := range ds.usingList {
+			if i > 0 {
+				sb.WriteString(", ")
+			}
+			u.WriteSQL(sb, args)
+		}
+	}
+
 	ds.whereList.WriteSQL(sb, args)
 	ds.returningList.WriteSQL(sb, args)
 }
 
+// Apply merges other's with, from, joins and where. This allows a scope used to filter a select to drive a delete. A
+// from item for the table being deleted from supplies its alias. Other from items and inner and cross joins are added
+// as using items. Outer joins and joins with using columns cause an error when the statement is built.
 func (ds *DeleteStatement) Apply(others ...*SelectStatement) *DeleteStatement {
+	ds = ds.mutable()
 	for _, other := range others {
+		ds.withList = append(ds.withList, other.withList...)
+
+		if other.from != nil {
+			ds.applyFrom(other.from)
+		}
+
+		for _, j := range other.joinList {
+			if (j.joinType != "join" && j.joinType != "cross join") || len(j.using) > 0 {
+				err := fmt.Errorf("pgsql: delete cannot apply %s %s", j.joinType, j.table)
+				ds.whereList = append(ds.whereList, &errorWriter{err: err})
+				continue
+			}
+
+			table, on := j.tableAndCondition()
+			ds.usingList = append(ds.usingList, table)
+			if on != nil {
+				ds.whereList = append(ds.whereList, on)
+			}
+		}
+
 		ds.whereList = append(ds.whereList, other.whereList...)
 	}
 
 	return ds
 }
+
+// applyFrom adds from as a using item unless it refers to the table being deleted from. In that case its alias, if
+// any, becomes the alias of the deleted table.
+func (ds *DeleteStatement) applyFrom(from SQLWriter) {
+	fs, ok := from.(*FormatString)
+	if !ok {
+		ds.usingList = append(ds.usingList, from)
+		return
+	}
+
+	fields := strings.Fields(fs.s)
+	if len(fields) == 0 || fields[0] != ds.tableName {
+		ds.usingList = append(ds.usingList, from)
+		return
+	}
+
+	var alias string
+	switch {
+	case len(fs.args) > 0:
+		ds.whereList = append(ds.whereList, &errorWriter{err: fmt.Errorf("pgsql: delete cannot apply from item %q", fs.s)})
+		return
+	case len(fields) == 1:
+		return
+	case len(fields) == 2:
+		alias = fields[1]
+	case len(fields) == 3 && strings.EqualFold(fields[1], "as"):
+		alias = fields[2]
+	default:
+		ds.whereList = append(ds.whereList, &errorWriter{err: fmt.Errorf("pgsql: delete cannot apply from item %q", fs.s)})
+		return
+	}
+
+	switch ds.alias {
+	case "":
+		ds.alias = alias
+	case alias:
+	default:
+		err := fmt.Errorf("pgsql: delete alias %s does not match from item %q", ds.alias, fs.s)
+		ds.whereList = append(ds.whereList, &errorWriter{err: err})
+	}
+}
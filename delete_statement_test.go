@@ -42,3 +42,85 @@ func TestDeleteStatementApply(t *testing.T) {
 	assert.Equal(t, `delete from people where (foo=$1) and (bar=$2)`, sql)
 	assert.Equal(t, []interface{}{43, 7}, args)
 }
+
+func TestDeleteStatementUsing(t *testing.T) {
+	a := pgsql.Delete("people").As("p").Using("teams t").Using("offices o").Where("t.id = p.team_id and o.id = p.office_id and t.name = ?", "red")
+	sql, args := pgsql.Build(a)
+	assert.Equal(t, `delete from people as p using teams t, offices o where (t.id = p.team_id and o.id = p.office_id and t.name = $1)`, sql)
+	assert.Equal(t, []interface{}{"red"}, args)
+}
+
+func TestDeleteStatementOnly(t *testing.T) {
+	a := pgsql.Delete("people").Only().Where("id = ?", 1)
+	sql, args := pgsql.Build(a)
+	assert.Equal(t, `delete from only people where (id = $1)`, sql)
+	assert.Equal(t, []interface{}{1}, args)
+}
+
+func TestDeleteStatementApplyJoins(t *testing.T) {
+	scope := pgsql.From("people p").
+		Join("teams t", "t.id = p.team_id and t.region = ?", "west").
+		JoinLateral("(select * from offices o where o.id = p.office_id and o.size > ?) o", "o.city = ?", 10, "Dallas").
+		CrossJoin("generate_series(1, ?) n", 3).
+		Where("p.age > ?", 30)
+
+	a := pgsql.Delete("people").As("p").Apply(scope)
+	sql, args := pgsql.Build(a)
+	assert.Equal(t, `delete from people as p using teams t, lateral (select * from offices o where o.id = p.office_id and o.size > $1) o, generate_series(1, $2) n where (t.id = p.team_id and t.region = $3) and (o.city = $4) and (p.age > $5)`, sql)
+	assert.Equal(t, []interface{}{10, 3, "west", "Dallas", 30}, args)
+}
+
+func TestDeleteStatementApplyFrom(t *testing.T) {
+	a := pgsql.Delete("people").Apply(pgsql.From("teams t").Where("t.id = people.team_id"))
+	sql, args := pgsql.Build(a)
+	assert.Equal(t, `delete from people using teams t where (t.id = people.team_id)`, sql)
+	assert.Empty(t, args)
+
+	a = pgsql.Delete("people").As("p").Apply(pgsql.From("people  as p").Where("p.age > ?", 30))
+	sql, args = pgsql.Build(a)
+	assert.Equal(t, `delete from people as p where (p.age > $1)`, sql)
+	assert.Equal(t, []interface{}{30}, args)
+}
+
+func TestDeleteStatementApplyAliasedFrom(t *testing.T) {
+	a := pgsql.Delete("people").Apply(pgsql.From("people p").Where("p.age > ?", 30))
+	sql, args := pgsql.Build(a)
+	assert.Equal(t, `delete from people as p where (p.age > $1)`, sql)
+	assert.Equal(t, []interface{}{30}, args)
+
+	scope := pgsql.From("people as p").Join("teams t", "t.id = p.team_id").Where("t.region = ?", "west")
+	sql, args = pgsql.Build(pgsql.Delete("people").Apply(scope))
+	assert.Equal(t, `delete from people as p using teams t where (t.id = p.team_id) and (t.region = $1)`, sql)
+	assert.Equal(t, []interface{}{"west"}, args)
+}
+
+func TestDeleteStatementApplyFromErrors(t *testing.T) {
+	_, _, err := pgsql.BuildE(pgsql.Delete("people").As("q").Apply(pgsql.From("people p")))
+	assert.EqualError(t, err, `pgsql: delete alias q does not match from item "people p"`)
+
+	_, _, err = pgsql.BuildE(pgsql.Delete("people").Apply(pgsql.From("people p tablesample bernoulli(?)", 10)))
+	assert.EqualError(t, err, `pgsql: delete cannot apply from item "people p tablesample bernoulli(?)"`)
+}
+
+func TestDeleteStatementApplyUnsupportedJoin(t *testing.T) {
+	a := pgsql.Delete("people").Apply(pgsql.From("people").LeftJoin("teams t", "t.id = people.team_id"))
+	_, _, err := pgsql.BuildE(a)
+	assert.EqualError(t, err, "pgsql: delete cannot apply left join teams t")
+
+	a = pgsql.Delete("people").Apply(pgsql.From("people").JoinUsing("teams", "team_id"))
+	_, _, err = pgsql.BuildE(a)
+	assert.EqualError(t, err, "pgsql: delete cannot apply join teams")
+}
+
+func TestDeleteStatementCloneCopiesNestedStatements(t *testing.T) {
+	stale := pgsql.Select("id").From("people")
+	teams := pgsql.Select("id").From("teams")
+	base := pgsql.Delete("people").With("stale", stale).Using(pgsql.Sub(teams).As("t")).Where("people.id in (select id from stale)")
+	c := base.Clone()
+	stale.Where("updated_at < ?", "2020-01-01")
+	teams.Where("closed")
+
+	sql, args := pgsql.Build(c)
+	assert.Equal(t, "with stale as (select id from people) delete from people using (select id from teams) as t where (people.id in (select id from stale))", sql)
+	assert.Empty(t, args)
+}
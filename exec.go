@@ -0,0 +1,54 @@
+package pgsql
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// Querier executes SQL. It is satisfied by *pgx.Conn, *pgxpool.Pool and pgx.Tx.
+type Querier interface {
+	Exec(ctx context.Context, sql string, arguments ...interface{}) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row
+}
+
+// Exec builds stmt and executes it with db.
+func Exec(ctx context.Context, db Querier, stmt SQLWriter, options ...ArgsOption) (pgconn.CommandTag, error) {
+	sql, args, err := BuildE(stmt, options...)
+	if err != nil {
+		return pgconn.CommandTag{}, err
+	}
+
+	return db.Exec(ctx, sql, args...)
+}
+
+// Query builds stmt and queries it with db.
+func Query(ctx context.Context, db Querier, stmt SQLWriter, options ...ArgsOption) (pgx.Rows, error) {
+	sql, args, err := BuildE(stmt, options...)
+	if err != nil {
+		return nil, err
+	}
+
+	return db.Query(ctx, sql, args...)
+}
+
+// QueryRow builds stmt and queries it with db. Any error building stmt is returned by the Scan method of the returned
+// row.
+func QueryRow(ctx context.Context, db Querier, stmt SQLWriter, options ...ArgsOption) pgx.Row {
+	sql, args, err := BuildE(stmt, options...)
+	if err != nil {
+		return errRow{err: err}
+	}
+
+	return db.QueryRow(ctx, sql, args...)
+}
+
+type errRow struct {
+	err error
+}
+
+func (r errRow) Scan(dest ...interface{}) error {
+	return r.err
+}
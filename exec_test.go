@@ -0,0 +1,93 @@
+package pgsql_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jackc/pgsql"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	_ pgsql.Querier = (*pgx.Conn)(nil)
+	_ pgsql.Querier = (*pgxpool.Pool)(nil)
+	_ pgsql.Querier = (pgx.Tx)(nil)
+)
+
+// mockQuerier records the last query and returns canned results.
+type mockQuerier struct {
+	sql  string
+	args []interface{}
+
+	rows pgx.Rows
+	err  error
+}
+
+func (m *mockQuerier) Exec(ctx context.Context, sql string, args ...interface{}) (pgconn.CommandTag, error) {
+	m.sql, m.args = sql, args
+	return pgconn.CommandTag{}, m.err
+}
+
+func (m *mockQuerier) Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error) {
+	m.sql, m.args = sql, args
+	return m.rows, m.err
+}
+
+type mockRow struct {
+	err error
+}
+
+func (r mockRow) Scan(dest ...interface{}) error {
+	return r.err
+}
+
+func (m *mockQuerier) QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row {
+	m.sql, m.args = sql, args
+	return mockRow{err: m.err}
+}
+
+func TestExec(t *testing.T) {
+	db := &mockQuerier{}
+	_, err := pgsql.Exec(context.Background(), db, pgsql.Delete("people").Where("age > ?", 90))
+	require.NoError(t, err)
+	assert.Equal(t, "delete from people where (age > $1)", db.sql)
+	assert.Equal(t, []interface{}{90}, db.args)
+}
+
+func TestExecBuildError(t *testing.T) {
+	db := &mockQuerier{}
+	_, err := pgsql.Exec(context.Background(), db, pgsql.Delete("people").Where("age > ?"))
+	assert.EqualError(t, err, `pgsql: "age > ?" has more placeholders than the 0 args provided`)
+	assert.Empty(t, db.sql)
+}
+
+func TestQuery(t *testing.T) {
+	db := &mockQuerier{err: errors.New("boom")}
+	_, err := pgsql.Query(context.Background(), db, pgsql.Select("id").From("people").Where("team_id = ? or owner_id = ?", 1, 1), pgsql.Deduplicate())
+	assert.EqualError(t, err, "boom")
+	assert.Equal(t, "select id from people where (team_id = $1 or owner_id = $1)", db.sql)
+	assert.Equal(t, []interface{}{1}, db.args)
+
+	db = &mockQuerier{}
+	_, err = pgsql.Query(context.Background(), db, pgsql.Select("?"))
+	assert.EqualError(t, err, `pgsql: "?" has more placeholders than the 0 args provided`)
+	assert.Empty(t, db.sql)
+}
+
+func TestQueryRow(t *testing.T) {
+	db := &mockQuerier{}
+	err := pgsql.QueryRow(context.Background(), db, pgsql.Insert("people").Data(pgsql.RowMap{"name": "Alice"}).Returning("id")).Scan()
+	assert.NoError(t, err)
+	assert.Equal(t, "insert into people (name) values ($1) returning id", db.sql)
+	assert.Equal(t, []interface{}{"Alice"}, db.args)
+
+	db = &mockQuerier{}
+	err = pgsql.QueryRow(context.Background(), db, pgsql.Update("people")).Scan()
+	assert.EqualError(t, err, "pgsql: update statement has no assignments")
+	assert.Empty(t, db.sql)
+}
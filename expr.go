@@ -0,0 +1,371 @@
+package pgsql
+
+import (
+	"database/sql/driver"
+	"errors"
+	"reflect"
+	"strings"
+)
+
+// Column is a column reference for use in expressions.
+type Column string
+
+// Col returns a reference to the column name. name may be qualified. e.g. "people.id". It is quoted if needed. See
+// StrictIdentifiers.
+func Col(name string) Column {
+	return Column(name)
+}
+
+func (c Column) WriteSQL(sb *strings.Builder, args *Args) {
+	writeName(sb, args, string(c))
+}
+
+// toSQLWriter returns v if it is a SQLWriter. Otherwise it returns v as a Param.
+func toSQLWriter(v interface{}) SQLWriter {
+	if w, ok := v.(SQLWriter); ok {
+		return w
+	}
+	return &Param{Value: v}
+}
+
+// toLeftOperand returns v as a Column if it is a string. Otherwise it returns toSQLWriter(v). A string on the left side
+// of a comparison is a column name. It is never useful to compare two parameters.
+func toLeftOperand(v interface{}) SQLWriter {
+	if s, ok := v.(string); ok {
+		return Column(s)
+	}
+	return toSQLWriter(v)
+}
+
+// parenthesizedExpr is implemented by expressions that can be combined with other conditions without being enclosed in
+// parentheses because they write their own or are a single term.
+type parenthesizedExpr interface {
+	isParenthesized() bool
+}
+
+// isParenthesized reports whether w can be combined with other conditions without being enclosed in parentheses.
+func isParenthesized(w SQLWriter) bool {
+	pe, ok := w.(parenthesizedExpr)
+	return ok && pe.isParenthesized()
+}
+
+type binaryExpr struct {
+	left  SQLWriter
+	op    string
+	right SQLWriter
+}
+
+func (be *binaryExpr) WriteSQL(sb *strings.Builder, args *Args) {
+	sb.WriteByte('(')
+	be.left.WriteSQL(sb, args)
+	sb.WriteByte(' ')
+	sb.WriteString(be.op)
+	sb.WriteByte(' ')
+	be.right.WriteSQL(sb, args)
+	sb.WriteByte(')')
+}
+
+func (be *binaryExpr) isParenthesized() bool { return true }
+
+func newBinaryExpr(left interface{}, op string, right interface{}) *binaryExpr {
+	return &binaryExpr{left: toLeftOperand(left), op: op, right: toSQLWriter(right)}
+}
+
+// Eq returns an expression that tests if left equals right. A string left operand is a column name. e.g.
+// Eq("status", "active") is written as "(status = $1)". Other operands that are not a SQLWriter are passed as
+// parameters. This applies to all comparison functions and to IsNull, Between and In.
+func Eq(left, right interface{}) SQLWriter {
+	return newBinaryExpr(left, "=", right)
+}
+
+func Ne(left, right interface{}) SQLWriter {
+	return newBinaryExpr(left, "<>", right)
+}
+
+func Lt(left, right interface{}) SQLWriter {
+	return newBinaryExpr(left, "<", right)
+}
+
+func Lte(left, right interface{}) SQLWriter {
+	return newBinaryExpr(left, "<=", right)
+}
+
+func Gt(left, right interface{}) SQLWriter {
+	return newBinaryExpr(left, ">", right)
+}
+
+func Gte(left, right interface{}) SQLWriter {
+	return newBinaryExpr(left, ">=", right)
+}
+
+func IsDistinctFrom(left, right interface{}) SQLWriter {
+	return newBinaryExpr(left, "is distinct from", right)
+}
+
+func IsNotDistinctFrom(left, right interface{}) SQLWriter {
+	return newBinaryExpr(left, "is not distinct from", right)
+}
+
+func Like(left, pattern interface{}) SQLWriter {
+	return newBinaryExpr(left, "like", pattern)
+}
+
+func NotLike(left, pattern interface{}) SQLWriter {
+	return newBinaryExpr(left, "not like", pattern)
+}
+
+func ILike(left, pattern interface{}) SQLWriter {
+	return newBinaryExpr(left, "ilike", pattern)
+}
+
+func NotILike(left, pattern interface{}) SQLWriter {
+	return newBinaryExpr(left, "not ilike", pattern)
+}
+
+type logicalExpr struct {
+	op    string
+	exprs []SQLWriter
+}
+
+func (le *logicalExpr) WriteSQL(sb *strings.Builder, args *Args) {
+	if len(le.exprs) == 1 {
+		le.exprs[0].WriteSQL(sb, args)
+		return
+	}
+
+	sb.WriteByte('(')
+	for i, e := range le.exprs {
+		if i > 0 {
+			sb.WriteByte(' ')
+			sb.WriteString(le.op)
+			sb.WriteByte(' ')
+		}
+		e.WriteSQL(sb, args)
+	}
+	sb.WriteByte(')')
+}
+
+func (le *logicalExpr) isParenthesized() bool {
+	return len(le.exprs) != 1 || isParenthesized(le.exprs[0])
+}
+
+// And returns an expression that is true when all exprs are true. It is true when exprs is empty.
+func And(exprs ...SQLWriter) SQLWriter {
+	if len(exprs) == 0 {
+		return rawSQL("true")
+	}
+	return &logicalExpr{op: "and", exprs: exprs}
+}
+
+// Or returns an expression that is true when any of exprs is true. It is false when exprs is empty.
+func Or(exprs ...SQLWriter) SQLWriter {
+	if len(exprs) == 0 {
+		return rawSQL("false")
+	}
+	return &logicalExpr{op: "or", exprs: exprs}
+}
+
+type notExpr struct {
+	expr SQLWriter
+}
+
+func (ne *notExpr) WriteSQL(sb *strings.Builder, args *Args) {
+	sb.WriteString("(not ")
+	ne.expr.WriteSQL(sb, args)
+	sb.WriteByte(')')
+}
+
+func (ne *notExpr) isParenthesized() bool { return true }
+
+func Not(expr SQLWriter) SQLWriter {
+	return &notExpr{expr: expr}
+}
+
+type postfixExpr struct {
+	expr SQLWriter
+	op   string
+}
+
+func (pe *postfixExpr) WriteSQL(sb *strings.Builder, args *Args) {
+	sb.WriteByte('(')
+	pe.expr.WriteSQL(sb, args)
+	sb.WriteByte(' ')
+	sb.WriteString(pe.op)
+	sb.WriteByte(')')
+}
+
+func (pe *postfixExpr) isParenthesized() bool { return true }
+
+func IsNull(expr interface{}) SQLWriter {
+	return &postfixExpr{expr: toLeftOperand(expr), op: "is null"}
+}
+
+func IsNotNull(expr interface{}) SQLWriter {
+	return &postfixExpr{expr: toLeftOperand(expr), op: "is not null"}
+}
+
+type betweenExpr struct {
+	expr SQLWriter
+	op   string
+	low  SQLWriter
+	high SQLWriter
+}
+
+func (be *betweenExpr) WriteSQL(sb *strings.Builder, args *Args) {
+	sb.WriteByte('(')
+	be.expr.WriteSQL(sb, args)
+	sb.WriteByte(' ')
+	sb.WriteString(be.op)
+	sb.WriteByte(' ')
+	be.low.WriteSQL(sb, args)
+	sb.WriteString(" and ")
+	be.high.WriteSQL(sb, args)
+	sb.WriteByte(')')
+}
+
+func (be *betweenExpr) isParenthesized() bool { return true }
+
+func Between(expr, low, high interface{}) SQLWriter {
+	return &betweenExpr{expr: toLeftOperand(expr), op: "between", low: toSQLWriter(low), high: toSQLWriter(high)}
+}
+
+func NotBetween(expr, low, high interface{}) SQLWriter {
+	return &betweenExpr{expr: toLeftOperand(expr), op: "not between", low: toSQLWriter(low), high: toSQLWriter(high)}
+}
+
+// InExpr is an expression that tests if a value is in a list of values or the results of a subquery. It is created by
+// In and NotIn.
+type InExpr struct {
+	left   SQLWriter
+	not    bool
+	values interface{}
+	useAny bool
+}
+
+// In returns an expression that tests if left is in values. If values is a SQLWriter such as a *SelectStatement it is
+// written as a subquery. If values is a slice or array each element is passed as a separate parameter. Use AsAny to
+// pass the entire slice as a single parameter instead. An empty slice is written as false as "in ()" is not valid SQL.
+// Byte slices and arrays and values that implement driver.Valuer are passed as a single parameter.
+func In(left, values interface{}) *InExpr {
+	return &InExpr{left: toLeftOperand(left), values: values}
+}
+
+// NotIn returns an expression that tests if left is not in values. It accepts the same arguments as In. An empty slice
+// is written as true.
+func NotIn(left, values interface{}) *InExpr {
+	return &InExpr{left: toLeftOperand(left), not: true, values: values}
+}
+
+// AsAny causes a slice to be passed as a single array parameter. In is written as "= any($1)" and NotIn is written as
+// "<> all($1)". This keeps the number of parameters and the SQL text constant regardless of the slice length.
+func (ie *InExpr) AsAny() *InExpr {
+	ie.useAny = true
+	return ie
+}
+
+func (ie *InExpr) isParenthesized() bool { return true }
+
+func (ie *InExpr) WriteSQL(sb *strings.Builder, args *Args) {
+	if w, ok := ie.values.(SQLWriter); ok {
+		w = unwrapSubquery(w)
+		if isNilSQLWriter(w) {
+			args.SetError(errors.New("pgsql: in subquery is nil"))
+			return
+		}
+		sb.WriteByte('(')
+		ie.left.WriteSQL(sb, args)
+		if ie.not {
+			sb.WriteString(" not in (")
+		} else {
+			sb.WriteString(" in (")
+		}
+		w.WriteSQL(sb, args)
+		sb.WriteString("))")
+		return
+	}
+
+	rv := reflect.ValueOf(ie.values)
+	isList := isListValue(rv)
+	if isList && rv.Len() == 0 {
+		if ie.not {
+			sb.WriteString("true")
+		} else {
+			sb.WriteString("false")
+		}
+		return
+	}
+
+	sb.WriteByte('(')
+	ie.left.WriteSQL(sb, args)
+
+	if isList && ie.useAny {
+		if ie.not {
+			sb.WriteString(" <> all(")
+		} else {
+			sb.WriteString(" = any(")
+		}
+		sb.WriteString(args.Use(ie.values).String())
+		sb.WriteString("))")
+		return
+	}
+
+	if ie.not {
+		sb.WriteString(" not in (")
+	} else {
+		sb.WriteString(" in (")
+	}
+	if isList {
+		for i := 0; i < rv.Len(); i++ {
+			if i > 0 {
+				sb.WriteString(", ")
+			}
+			sb.WriteString(args.Use(rv.Index(i).Interface()).String())
+		}
+	} else {
+		sb.WriteString(args.Use(ie.values).String())
+	}
+	sb.WriteString("))")
+}
+
+// isListValue reports whether rv is a slice or array whose elements are separate values. Byte slices and arrays such
+// as a UUID and values that implement driver.Valuer are single values.
+func isListValue(rv reflect.Value) bool {
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return false
+	}
+	if rv.Type().Elem().Kind() == reflect.Uint8 {
+		return false
+	}
+	if _, ok := rv.Interface().(driver.Valuer); ok {
+		return false
+	}
+	return true
+}
+
+type quantifiedExpr struct {
+	quantifier string
+	value      interface{}
+}
+
+func (qe *quantifiedExpr) WriteSQL(sb *strings.Builder, args *Args) {
+	sb.WriteString(qe.quantifier)
+	sb.WriteByte('(')
+	if w, ok := qe.value.(SQLWriter); ok && !isNilSQLWriter(w) {
+		unwrapSubquery(w).WriteSQL(sb, args)
+	} else {
+		sb.WriteString(args.Use(qe.value).String())
+	}
+	sb.WriteByte(')')
+}
+
+// Any returns an any array comparison operand. value may be a slice passed as a single parameter or a SQLWriter such as
+// a *SelectStatement. e.g. Eq(Col("id"), Any(ids)) is written as "(id = any($1))".
+func Any(value interface{}) SQLWriter {
+	return &quantifiedExpr{quantifier: "any", value: value}
+}
+
+// All returns an all array comparison operand. It accepts the same arguments as Any. e.g. Gt(Col("price"), All(prices))
+// is written as "(price > all($1))".
+func All(value interface{}) SQLWriter {
+	return &quantifiedExpr{quantifier: "all", value: value}
+}
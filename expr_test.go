@@ -0,0 +1,189 @@
+package pgsql_test
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"testing"
+
+	"github.com/jackc/pgsql"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestComparisonExprs(t *testing.T) {
+	tests := []struct {
+		expr pgsql.SQLWriter
+		sql  string
+	}{
+		{pgsql.Eq(pgsql.Col("a"), 1), "(a = $1)"},
+		{pgsql.Ne(pgsql.Col("a"), 1), "(a <> $1)"},
+		{pgsql.Lt(pgsql.Col("a"), 1), "(a < $1)"},
+		{pgsql.Lte(pgsql.Col("a"), 1), "(a <= $1)"},
+		{pgsql.Gt(pgsql.Col("a"), 1), "(a > $1)"},
+		{pgsql.Gte(pgsql.Col("a"), 1), "(a >= $1)"},
+		{pgsql.IsDistinctFrom(pgsql.Col("a"), 1), "(a is distinct from $1)"},
+		{pgsql.IsNotDistinctFrom(pgsql.Col("a"), 1), "(a is not distinct from $1)"},
+		{pgsql.Like(pgsql.Col("a"), 1), "(a like $1)"},
+		{pgsql.NotLike(pgsql.Col("a"), 1), "(a not like $1)"},
+		{pgsql.ILike(pgsql.Col("a"), 1), "(a ilike $1)"},
+		{pgsql.NotILike(pgsql.Col("a"), 1), "(a not ilike $1)"},
+		{pgsql.IsNull(pgsql.Col("a")), "(a is null)"},
+		{pgsql.IsNotNull(pgsql.Col("a")), "(a is not null)"},
+		{pgsql.Between(pgsql.Col("a"), 1, 2), "(a between $1 and $2)"},
+		{pgsql.NotBetween(pgsql.Col("a"), 1, 2), "(a not between $1 and $2)"},
+	}
+
+	for i, tt := range tests {
+		sql, _ := pgsql.Build(tt.expr)
+		assert.Equalf(t, tt.sql, sql, "%d", i)
+	}
+}
+
+func TestStringLeftOperandIsColumn(t *testing.T) {
+	tests := []struct {
+		expr pgsql.SQLWriter
+		sql  string
+		args []interface{}
+	}{
+		{pgsql.Eq("status", "active"), "(status = $1)", []interface{}{"active"}},
+		{pgsql.Gt("people.age", 21), "(people.age > $1)", []interface{}{21}},
+		{pgsql.IsNull("x"), "(x is null)", nil},
+		{pgsql.Between("x", 1, 2), "(x between $1 and $2)", []interface{}{1, 2}},
+		{pgsql.In("id", []int{1, 2}), "(id in ($1, $2))", []interface{}{1, 2}},
+		{pgsql.Eq(1, "x"), "($1 = $2)", []interface{}{1, "x"}},
+	}
+
+	for i, tt := range tests {
+		sql, args, err := pgsql.BuildE(tt.expr)
+		assert.NoErrorf(t, err, "%d", i)
+		assert.Equalf(t, tt.sql, sql, "%d", i)
+		assert.Equalf(t, tt.args, args, "%d", i)
+	}
+}
+
+func TestLogicalExprs(t *testing.T) {
+	expr := pgsql.Or(
+		pgsql.And(pgsql.Eq(pgsql.Col("a"), 1), pgsql.Gt(pgsql.Col("b"), pgsql.Col("c"))),
+		pgsql.Not(pgsql.IsNull(pgsql.Col("d"))),
+		pgsql.Eq(pgsql.Col("e"), "x"),
+	)
+	sql, args := pgsql.Build(expr)
+	assert.Equal(t, "(((a = $1) and (b > c)) or (not (d is null)) or (e = $2))", sql)
+	assert.Equal(t, []interface{}{1, "x"}, args)
+
+	sql, _ = pgsql.Build(pgsql.And(pgsql.Eq(pgsql.Col("a"), 1)))
+	assert.Equal(t, "(a = $1)", sql)
+
+	sql, _ = pgsql.Build(pgsql.And())
+	assert.Equal(t, "true", sql)
+
+	sql, _ = pgsql.Build(pgsql.Or())
+	assert.Equal(t, "false", sql)
+}
+
+func TestWhereExpr(t *testing.T) {
+	a := pgsql.Select("*").From("people").
+		WhereExpr(pgsql.Or(pgsql.Eq(pgsql.Col("name"), "Alice"), pgsql.Gt(pgsql.Col("age"), 30))).
+		Where("team_id = ?", 7)
+	sql, args := pgsql.Build(a)
+	assert.Equal(t, "select * from people where ((name = $1) or (age > $2)) and (team_id = $3)", sql)
+	assert.Equal(t, []interface{}{"Alice", 30, 7}, args)
+
+	u := pgsql.Update("people").Setf("active = ?", false).WhereExpr(pgsql.Lt(pgsql.Col("last_seen"), "2020-01-01"))
+	sql, args = pgsql.Build(u)
+	assert.Equal(t, "update people set active = $1 where (last_seen < $2)", sql)
+	assert.Equal(t, []interface{}{false, "2020-01-01"}, args)
+
+	d := pgsql.Delete("people").WhereExpr(pgsql.IsNull(pgsql.Col("team_id")))
+	sql, args = pgsql.Build(d)
+	assert.Equal(t, "delete from people where (team_id is null)", sql)
+	assert.Empty(t, args)
+}
+
+func TestWhereExprNil(t *testing.T) {
+	_, _, err := pgsql.BuildE(pgsql.WhereExpr((*pgsql.InExpr)(nil)))
+	assert.EqualError(t, err, "pgsql: condition is nil")
+
+	_, _, err = pgsql.BuildE(pgsql.Select("*").HavingExpr(nil))
+	assert.EqualError(t, err, "pgsql: condition is nil")
+}
+
+func TestInExpr(t *testing.T) {
+	sql, args := pgsql.Build(pgsql.In(pgsql.Col("id"), []int{1, 2, 3}))
+	assert.Equal(t, "(id in ($1, $2, $3))", sql)
+	assert.Equal(t, []interface{}{1, 2, 3}, args)
+
+	sql, args = pgsql.Build(pgsql.NotIn(pgsql.Col("id"), [2]string{"a", "b"}))
+	assert.Equal(t, "(id not in ($1, $2))", sql)
+	assert.Equal(t, []interface{}{"a", "b"}, args)
+
+	sql, args = pgsql.Build(pgsql.In(pgsql.Col("id"), 7))
+	assert.Equal(t, "(id in ($1))", sql)
+	assert.Equal(t, []interface{}{7}, args)
+}
+
+type valuerList []int
+
+func (vl valuerList) Value() (driver.Value, error) {
+	return fmt.Sprint([]int(vl)), nil
+}
+
+func TestInExprScalarSlices(t *testing.T) {
+	b := []byte{1, 2, 3}
+	sql, args := pgsql.Build(pgsql.In(pgsql.Col("digest"), b))
+	assert.Equal(t, "(digest in ($1))", sql)
+	assert.Equal(t, []interface{}{b}, args)
+
+	uuid := [16]byte{1}
+	sql, args = pgsql.Build(pgsql.NotIn(pgsql.Col("id"), uuid))
+	assert.Equal(t, "(id not in ($1))", sql)
+	assert.Equal(t, []interface{}{uuid}, args)
+
+	vl := valuerList{1, 2}
+	sql, args = pgsql.Build(pgsql.In(pgsql.Col("ids"), vl))
+	assert.Equal(t, "(ids in ($1))", sql)
+	assert.Equal(t, []interface{}{vl}, args)
+}
+
+func TestInExprAsAny(t *testing.T) {
+	ids := []int{1, 2, 3}
+	sql, args := pgsql.Build(pgsql.In(pgsql.Col("id"), ids).AsAny())
+	assert.Equal(t, "(id = any($1))", sql)
+	assert.Equal(t, []interface{}{ids}, args)
+
+	sql, args = pgsql.Build(pgsql.NotIn(pgsql.Col("id"), ids).AsAny())
+	assert.Equal(t, "(id <> all($1))", sql)
+	assert.Equal(t, []interface{}{ids}, args)
+}
+
+func TestInExprEmptySlice(t *testing.T) {
+	for _, in := range []*pgsql.InExpr{pgsql.In(pgsql.Col("id"), []int{}), pgsql.In(pgsql.Col("id"), []int(nil)).AsAny()} {
+		sql, args := pgsql.Build(in)
+		assert.Equal(t, "false", sql)
+		assert.Empty(t, args)
+	}
+
+	sql, args := pgsql.Build(pgsql.NotIn(pgsql.Col("id"), []string{}))
+	assert.Equal(t, "true", sql)
+	assert.Empty(t, args)
+}
+
+func TestInExprSubquery(t *testing.T) {
+	a := pgsql.Select("*").From("people").
+		Where("age > ?", 30).
+		WhereExpr(pgsql.In(pgsql.Col("team_id"), pgsql.Select("id").From("teams").Where("name = ?", "red"))).
+		WhereExpr(pgsql.NotIn(pgsql.Col("id"), pgsql.Select("person_id").From("banned")))
+	sql, args := pgsql.Build(a)
+	assert.Equal(t, "select * from people where (age > $1) and (team_id in (select id from teams where (name = $2))) and (id not in (select person_id from banned))", sql)
+	assert.Equal(t, []interface{}{30, "red"}, args)
+}
+
+func TestAnyAndAll(t *testing.T) {
+	ids := []int{1, 2}
+	sql, args := pgsql.Build(pgsql.Eq(pgsql.Col("id"), pgsql.Any(ids)))
+	assert.Equal(t, "(id = any($1))", sql)
+	assert.Equal(t, []interface{}{ids}, args)
+
+	sql, args = pgsql.Build(pgsql.Gt(pgsql.Col("price"), pgsql.All(pgsql.Select("price").From("products").Where("brand = ?", "acme"))))
+	assert.Equal(t, "(price > all(select price from products where (brand = $1)))", sql)
+	assert.Equal(t, []interface{}{"acme"}, args)
+}
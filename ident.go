@@ -0,0 +1,168 @@
+package pgsql
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// StrictIdentifiers causes table and column names that are not valid identifiers to be reported as an error instead of
+// being written unchanged. Names given as an Ident or already quoted are always valid.
+func StrictIdentifiers() ArgsOption {
+	return func(a *Args) {
+		a.strictIdentifiers = true
+	}
+}
+
+// String returns i quoted and joined with dots. It may be used anywhere a table or column name is accepted.
+func (i Ident) String() string {
+	return pgx.Identifier(i).Sanitize()
+}
+
+// writeName writes the table or column name. name may be qualified with dots. Each part that is a reserved word is
+// quoted. Parts that are already quoted are written unchanged. If name is not a valid identifier it is written
+// unchanged or, with StrictIdentifiers, an error is reported.
+func writeName(sb *strings.Builder, args *Args, name string) {
+	parts, ok := splitName(name)
+	if !ok {
+		if args.strictIdentifiers {
+			args.SetError(fmt.Errorf("pgsql: %q is not a valid identifier", name))
+		}
+		sb.WriteString(name)
+		return
+	}
+
+	for i, p := range parts {
+		if i > 0 {
+			sb.WriteByte('.')
+		}
+
+		lower := strings.ToLower(p)
+		if _, reserved := reservedWords[lower]; reserved {
+			// An unquoted identifier is folded to lower case so the quoted lower case form refers to the same object.
+			sb.WriteByte('"')
+			sb.WriteString(lower)
+			sb.WriteByte('"')
+		} else {
+			sb.WriteString(p)
+		}
+	}
+}
+
+// writeTableName writes the table name of an insert, update or delete. name may be followed by an alias such as
+// "people p" or "people as p". The table name and alias are each written as by writeName.
+func writeTableName(sb *strings.Builder, args *Args, name string) {
+	if table, as, alias, ok := splitTableAlias(name); ok {
+		writeName(sb, args, table)
+		sb.WriteByte(' ')
+		if as != "" {
+			sb.WriteString(as)
+			sb.WriteByte(' ')
+		}
+		writeName(sb, args, alias)
+		return
+	}
+
+	writeName(sb, args, name)
+}
+
+// splitTableAlias splits a "name alias" or "name as alias" table reference. ok is false if name is a single name or the
+// parts are not identifiers.
+func splitTableAlias(name string) (table, as, alias string, ok bool) {
+	if _, ok := splitName(name); ok {
+		return "", "", "", false
+	}
+
+	s := strings.TrimSpace(name)
+	i := strings.LastIndexAny(s, " \t\n")
+	if i == -1 {
+		return "", "", "", false
+	}
+	table, alias = strings.TrimSpace(s[:i]), s[i+1:]
+
+	if j := strings.LastIndexAny(table, " \t\n"); j != -1 && strings.EqualFold(table[j+1:], "as") {
+		table, as = strings.TrimSpace(table[:j]), table[j+1:]
+	}
+
+	if _, ok := splitName(table); !ok {
+		return "", "", "", false
+	}
+	if _, ok := splitName(alias); !ok {
+		return "", "", "", false
+	}
+	return table, as, alias, true
+}
+
+// nameOf returns v as a table or column name. v may be a string or an Ident.
+func nameOf(kind string, v interface{}) (string, error) {
+	switch v := v.(type) {
+	case string:
+		return v, nil
+	case Ident:
+		return v.String(), nil
+	default:
+		return "", fmt.Errorf("pgsql: %s must be a string or Ident, not %T", kind, v)
+	}
+}
+
+// splitName splits name into its dot separated parts. ok is false if any part is not an unquoted or quoted
+// identifier.
+func splitName(name string) (parts []string, ok bool) {
+	for {
+		var n int
+		if strings.HasPrefix(name, `"`) {
+			n = skipQuoted(name, 1, '"', false)
+			if n < 3 || name[n-1] != '"' {
+				return nil, false
+			}
+		} else {
+			for n < len(name) && isIdentByte(name[n]) {
+				n++
+			}
+			if n == 0 || ('0' <= name[0] && name[0] <= '9') || name[0] == '$' {
+				return nil, false
+			}
+		}
+
+		parts = append(parts, name[:n])
+		name = name[n:]
+		if name == "" {
+			return parts, true
+		}
+
+		if name[0] != '.' {
+			return nil, false
+		}
+		name = name[1:]
+	}
+}
+
+// nameList writes names separated by commas.
+type nameList []string
+
+func (nl nameList) WriteSQL(sb *strings.Builder, args *Args) {
+	for i, n := range nl {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		writeName(sb, args, n)
+	}
+}
+
+// reservedWords are the PostgreSQL key words that cannot be used as a table or column name without quoting.
+var reservedWords = map[string]struct{}{
+	"all": {}, "analyse": {}, "analyze": {}, "and": {}, "any": {}, "array": {}, "as": {}, "asc": {}, "asymmetric": {},
+	"authorization": {}, "binary": {}, "both": {}, "case": {}, "cast": {}, "check": {}, "collate": {}, "collation": {},
+	"column": {}, "concurrently": {}, "constraint": {}, "create": {}, "cross": {}, "current_catalog": {},
+	"current_date": {}, "current_role": {}, "current_schema": {}, "current_time": {}, "current_timestamp": {},
+	"current_user": {}, "default": {}, "deferrable": {}, "desc": {}, "distinct": {}, "do": {}, "else": {}, "end": {},
+	"except": {}, "false": {}, "fetch": {}, "for": {}, "foreign": {}, "freeze": {}, "from": {}, "full": {}, "grant": {},
+	"group": {}, "having": {}, "ilike": {}, "in": {}, "initially": {}, "inner": {}, "intersect": {}, "into": {}, "is": {},
+	"isnull": {}, "join": {}, "lateral": {}, "leading": {}, "left": {}, "like": {}, "limit": {}, "localtime": {},
+	"localtimestamp": {}, "natural": {}, "not": {}, "notnull": {}, "null": {}, "offset": {}, "on": {}, "only": {}, "or": {},
+	"order": {}, "outer": {}, "overlaps": {}, "placing": {}, "primary": {}, "references": {}, "returning": {}, "right": {},
+	"select": {}, "session_user": {}, "similar": {}, "some": {}, "symmetric": {}, "system_user": {}, "table": {},
+	"tablesample": {}, "then": {}, "to": {}, "trailing": {}, "true": {}, "union": {}, "unique": {}, "user": {}, "using": {},
+	"variadic": {}, "verbose": {}, "when": {}, "where": {}, "window": {}, "with": {},
+}
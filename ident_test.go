@@ -0,0 +1,110 @@
+package pgsql_test
+
+import (
+	"testing"
+
+	"github.com/jackc/pgsql"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIdentString(t *testing.T) {
+	assert.Equal(t, `"public"."people"`, pgsql.Ident{"public", "people"}.String())
+}
+
+func TestReservedWordNamesAreQuoted(t *testing.T) {
+	a := pgsql.Insert("user").Data(pgsql.RowMap{"order": 1, "name": "Alice"}).OnConflict("order").DoUpdate(pgsql.Excluded("name", "order"))
+	sql, args := pgsql.Build(a)
+	assert.Equal(t, `insert into "user" (name, "order") values ($1,$2) on conflict ("order") do update set name = excluded.name, "order" = excluded."order"`, sql)
+	assert.Equal(t, []interface{}{"Alice", 1}, args)
+
+	u := pgsql.Update("public.User").Set(pgsql.RowMap{"Order": 1}).WhereExpr(pgsql.Eq(pgsql.Col("user.Group"), 2))
+	sql, args = pgsql.Build(u)
+	assert.Equal(t, `update public."user" set "order" = $1 where ("user"."group" = $2)`, sql)
+	assert.Equal(t, []interface{}{1, 2}, args)
+
+	d := pgsql.Delete("table").As("from")
+	sql, _ = pgsql.Build(d)
+	assert.Equal(t, `delete from "table" as "from"`, sql)
+}
+
+func TestQuotedNamesAreUnchanged(t *testing.T) {
+	a := pgsql.Insert(pgsql.Ident{"my schema", "People"}.String()).Columns(`"First Name"`, `"a""b".c`)
+	sql, _ := pgsql.Build(a, pgsql.StrictIdentifiers())
+	assert.Equal(t, `insert into "my schema"."People" ("First Name", "a""b".c)`, sql)
+}
+
+func TestIdentTableAndColumnNames(t *testing.T) {
+	table := pgsql.Ident{"my schema", "People"}
+
+	sql, _, err := pgsql.BuildE(pgsql.Insert(table).ColumnIdents(pgsql.Ident{"First Name"}, pgsql.Ident{"order"}).DefaultValues(), pgsql.StrictIdentifiers())
+	assert.NoError(t, err)
+	assert.Equal(t, `insert into "my schema"."People" ("First Name", "order") default values`, sql)
+
+	sql, _, err = pgsql.BuildE(pgsql.Update(table).Setf("a = 1"), pgsql.StrictIdentifiers())
+	assert.NoError(t, err)
+	assert.Equal(t, `update "my schema"."People" set a = 1`, sql)
+
+	sql, _, err = pgsql.BuildE(pgsql.Delete(table).As("p"), pgsql.StrictIdentifiers())
+	assert.NoError(t, err)
+	assert.Equal(t, `delete from "my schema"."People" as p`, sql)
+
+	_, _, err = pgsql.BuildE(pgsql.Update(42).Setf("a = 1"))
+	assert.EqualError(t, err, "pgsql: update table name must be a string or Ident, not int")
+}
+
+func TestTableAliases(t *testing.T) {
+	tests := []struct {
+		stmt pgsql.SQLWriter
+		sql  string
+	}{
+		{pgsql.Update("people p").Setf("a = 1"), `update people p set a = 1`},
+		{pgsql.Update("public.user as u").Setf("a = 1"), `update public."user" as u set a = 1`},
+		{pgsql.Insert(`"My People" AS p`).DefaultValues(), `insert into "My People" AS p default values`},
+		{pgsql.BulkUpdate("people p", pgsql.Values().Row(1, "a"), []string{"id"}, []string{"name"}), `update people p set name = v.name from (values ($1,$2)) as v(id, name) where (p.id = v.id)`},
+	}
+
+	for i, tt := range tests {
+		sql, _, err := pgsql.BuildE(tt.stmt, pgsql.StrictIdentifiers())
+		assert.NoErrorf(t, err, "%d", i)
+		assert.Equalf(t, tt.sql, sql, "%d", i)
+	}
+}
+
+func TestInvalidIdentifiers(t *testing.T) {
+	a := pgsql.Update("people p q").Setf("a = 1")
+	sql, _ := pgsql.Build(a)
+	assert.Equal(t, `update people p q set a = 1`, sql)
+
+	_, _, err := pgsql.BuildE(a, pgsql.StrictIdentifiers())
+	assert.EqualError(t, err, `pgsql: "people p q" is not a valid identifier`)
+
+	for _, name := range []string{"a;drop table people", "1a", "a.", ".a", `"a`, `""`, "a..b", "$1", ""} {
+		_, _, err := pgsql.BuildE(pgsql.Select("*").From("t").WhereExpr(pgsql.IsNull(pgsql.Col(name))), pgsql.StrictIdentifiers())
+		assert.Errorf(t, err, "%q", name)
+	}
+
+	for _, name := range []string{"a", "a1", "_a", "a$b", "schema.table.column", `"weird name".b`, "über"} {
+		_, _, err := pgsql.BuildE(pgsql.Select("*").From("t").WhereExpr(pgsql.IsNull(pgsql.Col(name))), pgsql.StrictIdentifiers())
+		assert.NoErrorf(t, err, "%q", name)
+	}
+}
+
+func TestStrictIdentifiersAppliesToAllNames(t *testing.T) {
+	tests := []pgsql.SQLWriter{
+		pgsql.Insert("people").Columns("name; --").DefaultValues(),
+		pgsql.Insert("people").DefaultValues().OnConflictOnConstraint("x y").DoNothing(),
+		pgsql.Delete("people").As("p q"),
+		pgsql.Select("*").From("t").JoinUsing("u", "a b"),
+		pgsql.Select("*").From("t").ForUpdate().Of("t u"),
+		pgsql.Select("*").From("t").With("a b", pgsql.Select("1")),
+		pgsql.BulkUpdate("people", pgsql.Values().Row(1, 2), []string{"id"}, []string{"a b"}),
+	}
+
+	for i, stmt := range tests {
+		_, _, err := pgsql.BuildE(stmt)
+		assert.NoErrorf(t, err, "%d", i)
+
+		_, _, err = pgsql.BuildE(stmt, pgsql.StrictIdentifiers())
+		assert.Errorf(t, err, "%d", i)
+	}
+}
@@ -1,29 +1,59 @@
 package pgsql
 
 import (
+	"errors"
 	"strings"
 )
 
 type InsertStatement struct {
+	withList      withList
 	tableName     string
+	tableNameErr  error
 	columns       []string
+	overriding    string
 	values        SQLWriter
+	onConflict    *onConflictClause
 	returningList returningList
+
+	frozen bool
 }
 
-func Insert(tableName string) *InsertStatement {
-	return &InsertStatement{tableName: tableName}
+// Insert returns a statement that inserts into tableName. tableName may be a string or an Ident. A string may include an
+// alias such as "people as p".
+func Insert(tableName interface{}) *InsertStatement {
+	name, err := nameOf("insert table name", tableName)
+	return &InsertStatement{tableName: name, tableNameErr: err}
 }
 
 func (is *InsertStatement) InsertStatement() (*InsertStatement, error) {
 	return is, nil
 }
 
+func (is *InsertStatement) With(name string, query SQLWriter) *InsertStatement {
+	is = is.mutable()
+	is.withList = append(is.withList, &CommonTableExpression{Name: name, Query: query})
+	return is
+}
+
+func (is *InsertStatement) WithRecursive(name string, query SQLWriter) *InsertStatement {
+	is = is.mutable()
+	is.withList = append(is.withList, &CommonTableExpression{Name: name, Recursive: true, Query: query})
+	return is
+}
+
+// WithCTE adds cte to the with clause. Use this instead of With when a column list or materialization is needed.
+func (is *InsertStatement) WithCTE(cte *CommonTableExpression) *InsertStatement {
+	is = is.mutable()
+	is.withList = append(is.withList, cte)
+	return is
+}
+
 type Insertable interface {
 	InsertData() ([]string, *ValuesStatement)
 }
 
 func (is *InsertStatement) Data(data Insertable) *InsertStatement {
+	is = is.mutable()
 	columns, values := data.InsertData()
 	is.Columns(columns...)
 	is.Values(values)
@@ -31,40 +61,262 @@ func (is *InsertStatement) Data(data Insertable) *InsertStatement {
 }
 
 func (is *InsertStatement) Columns(columns ...string) *InsertStatement {
+	is = is.mutable()
 	is.columns = columns
 	return is
 }
 
+// ColumnIdents sets the column list from Idents. e.g. ColumnIdents(Ident{"First Name"}) is written as ("First Name").
+func (is *InsertStatement) ColumnIdents(columns ...Ident) *InsertStatement {
+	names := make([]string, len(columns))
+	for i, c := range columns {
+		names[i] = c.String()
+	}
+	return is.Columns(names...)
+}
+
 func (is *InsertStatement) Values(vs *ValuesStatement) *InsertStatement {
+	is = is.mutable()
+	if vs == nil {
+		is.values = &errorWriter{err: errors.New("pgsql: insert values statement is nil")}
+		return is
+	}
 	is.values = vs
 	return is
 }
 
+// OnConflict adds an on conflict clause with an optional conflict target of columns. It must be followed by DoNothing
+// or DoUpdate.
+func (is *InsertStatement) OnConflict(columns ...string) *InsertStatement {
+	is = is.mutable()
+	is.onConflict = &onConflictClause{columns: columns, err: is.replacedConflictClauseErr()}
+	return is
+}
+
+// OnConflictWhere adds an index predicate to the conflict target. This allows a partial unique index to be inferred. It
+// must follow OnConflict with at least one column.
+func (is *InsertStatement) OnConflictWhere(s string, args ...interface{}) *InsertStatement {
+	is = is.mutable()
+	if is.onConflict == nil {
+		is.onConflict = &onConflictClause{err: errors.New("pgsql: OnConflictWhere requires a preceding OnConflict")}
+	}
+	is.onConflict.targetWhereList = append(is.onConflict.targetWhereList, &FormatString{s: s, args: args})
+	return is
+}
+
+// OnConflictOnConstraint adds an on conflict clause that targets the constraint name.
+func (is *InsertStatement) OnConflictOnConstraint(name string) *InsertStatement {
+	is = is.mutable()
+	is.onConflict = &onConflictClause{constraint: name, err: is.replacedConflictClauseErr()}
+	return is
+}
+
+// replacedConflictClauseErr returns the error of the current on conflict clause so it is not lost when the clause is
+// replaced.
+func (is *InsertStatement) replacedConflictClauseErr() error {
+	if is.onConflict == nil {
+		return nil
+	}
+	return is.onConflict.err
+}
+
+func (is *InsertStatement) DoNothing() *InsertStatement {
+	is = is.mutable()
+	oc := is.conflictClause()
+	oc.doNothing = true
+	oc.assignments = nil
+	return is
+}
+
+// DoUpdate sets the conflict action to update the existing row with data. The row proposed for insertion is available
+// as excluded. See Excluded.
+func (is *InsertStatement) DoUpdate(data Updateable) *InsertStatement {
+	is = is.mutable()
+	oc := is.conflictClause()
+	oc.doNothing = false
+	oc.assignments = data.UpdateData()
+	return is
+}
+
+// DoUpdateWhere adds a condition that must be true for the existing row to be updated.
+func (is *InsertStatement) DoUpdateWhere(s string, args ...interface{}) *InsertStatement {
+	is = is.mutable()
+	is.conflictClause().updateWhereList = append(is.conflictClause().updateWhereList, &FormatString{s: s, args: args})
+	return is
+}
+
+func (is *InsertStatement) conflictClause() *onConflictClause {
+	if is.onConflict == nil {
+		is.onConflict = &onConflictClause{}
+	}
+	return is.onConflict
+}
+
+// Select sets the rows to insert to the results of ss.
+func (is *InsertStatement) Select(ss *SelectStatement) *InsertStatement {
+	is = is.mutable()
+	if ss == nil {
+		is.values = &errorWriter{err: errors.New("pgsql: insert select statement is nil")}
+		return is
+	}
+	is.values = ss
+	return is
+}
+
+// DefaultValues sets the statement to insert a single row filled with default values.
+func (is *InsertStatement) DefaultValues() *InsertStatement {
+	is = is.mutable()
+	is.values = rawSQL("default values")
+	return is
+}
+
+// OverridingSystemValue allows explicit values to be inserted into identity columns defined as generated always.
+func (is *InsertStatement) OverridingSystemValue() *InsertStatement {
+	is = is.mutable()
+	is.overriding = "overriding system value"
+	return is
+}
+
+// OverridingUserValue causes values supplied for identity columns defined as generated by default to be ignored.
+func (is *InsertStatement) OverridingUserValue() *InsertStatement {
+	is = is.mutable()
+	is.overriding = "overriding user value"
+	return is
+}
+
 func (is *InsertStatement) Returning(s string, args ...interface{}) *InsertStatement {
+	is = is.mutable()
 	is.returningList = append(is.returningList, &FormatString{s: s, args: args})
 	return is
 }
 
+// Clone returns a copy of is that can be modified without affecting is. Common table expressions and the values or
+// select statement are copied too. Other SQLWriters are shared. The copy is not frozen.
+func (is *InsertStatement) Clone() *InsertStatement {
+	c := *is
+	c.withList = is.withList.clone()
+	c.columns = cloneSlice(is.columns)
+	c.values = cloneStatement(is.values)
+	if is.onConflict != nil {
+		oc := *is.onConflict
+		oc.columns = cloneSlice(oc.columns)
+		oc.targetWhereList = cloneSlice(oc.targetWhereList)
+		oc.assignments = cloneSlice(oc.assignments)
+		oc.updateWhereList = cloneSlice(oc.updateWhereList)
+		c.onConflict = &oc
+	}
+	c.returningList = cloneSlice(is.returningList)
+	c.frozen = false
+	return &c
+}
+
+// Freeze makes is copy-on-write. See SelectStatement.Freeze.
+func (is *InsertStatement) Freeze() *InsertStatement {
+	is.frozen = true
+	return is
+}
+
+func (is *InsertStatement) mutable() *InsertStatement {
+	if is.frozen {
+		return is.Clone()
+	}
+	return is
+}
+
 func (is *InsertStatement) WriteSQL(sb *strings.Builder, args *Args) {
+	if is.tableNameErr != nil {
+		args.SetError(is.tableNameErr)
+	} else if is.tableName == "" {
+		args.SetError(errors.New("pgsql: insert table name is empty"))
+	}
+
+	is.withList.WriteSQL(sb, args)
 	sb.WriteString("insert into ")
-	sb.WriteString(is.tableName)
+	writeTableName(sb, args, is.tableName)
 	sb.WriteByte(' ')
 
+	needSpace := false
 	if len(is.columns) > 0 {
 		sb.WriteByte('(')
-		for i, c := range is.columns {
-			if i > 0 {
-				sb.WriteString(", ")
-			}
-			sb.WriteString(c)
-		}
+		nameList(is.columns).WriteSQL(sb, args)
 		sb.WriteByte(')')
+		needSpace = true
+	}
+
+	if is.overriding != "" {
+		if needSpace {
+			sb.WriteByte(' ')
+		}
+		sb.WriteString(is.overriding)
+		needSpace = true
 	}
 
 	if is.values != nil {
-		sb.WriteByte(' ')
+		if needSpace {
+			sb.WriteByte(' ')
+		}
 		is.values.WriteSQL(sb, args)
 	}
 
+	if is.onConflict != nil {
+		is.onConflict.WriteSQL(sb, args)
+	}
+
 	is.returningList.WriteSQL(sb, args)
 }
+
+type onConflictClause struct {
+	columns         []string
+	targetWhereList whereList
+	constraint      string
+
+	doNothing       bool
+	assignments     []*Assignment
+	updateWhereList whereList
+
+	err error
+}
+
+func (oc *onConflictClause) WriteSQL(sb *strings.Builder, args *Args) {
+	if oc.err != nil {
+		args.SetError(oc.err)
+	}
+	if len(oc.targetWhereList) > 0 && (oc.constraint != "" || len(oc.columns) == 0) {
+		args.SetError(errors.New("pgsql: OnConflictWhere requires conflict columns"))
+	}
+
+	sb.WriteString(" on conflict")
+
+	if oc.constraint != "" {
+		sb.WriteString(" on constraint ")
+		writeName(sb, args, oc.constraint)
+	} else if len(oc.columns) > 0 {
+		sb.WriteString(" (")
+		nameList(oc.columns).WriteSQL(sb, args)
+		sb.WriteByte(')')
+		oc.targetWhereList.WriteSQL(sb, args)
+	}
+
+	if oc.doNothing {
+		sb.WriteString(" do nothing")
+	} else if len(oc.assignments) > 0 {
+		if oc.constraint == "" && len(oc.columns) == 0 {
+			args.SetError(errors.New("pgsql: on conflict do update requires a conflict target"))
+		}
+		sb.WriteString(" do update set ")
+		writeAssignments(sb, args, oc.assignments)
+		oc.updateWhereList.WriteSQL(sb, args)
+	} else {
+		args.SetError(errors.New("pgsql: on conflict requires DoNothing or DoUpdate with at least one assignment"))
+	}
+}
+
+// Excluded returns assignments that set each column to the value proposed for insertion. It is intended for use with
+// DoUpdate. e.g. Excluded("name", "age") is rendered as "name = excluded.name, age = excluded.age".
+func Excluded(columns ...string) Assignments {
+	assignments := make(Assignments, len(columns))
+	for i, c := range columns {
+		assignments[i] = &Assignment{Left: Column(c), Right: Column("excluded." + c)}
+	}
+	return assignments
+}
@@ -48,3 +48,98 @@ func TestInsertStatementReturning(t *testing.T) {
 	assert.Equal(t, "insert into people (age, name) values ($1,$2) returning id", sql)
 	assert.Equal(t, []interface{}{30, "Alice"}, args)
 }
+
+func TestInsertStatementOnConflictDoNothing(t *testing.T) {
+	a := pgsql.Insert("people")
+	a.Data(pgsql.RowMap{"name": "Alice", "age": 30})
+	a.OnConflict().DoNothing()
+	sql, args := pgsql.Build(a)
+	assert.Equal(t, "insert into people (age, name) values ($1,$2) on conflict do nothing", sql)
+	assert.Equal(t, []interface{}{30, "Alice"}, args)
+
+	a.OnConflictOnConstraint("people_name_key").DoNothing().Returning("id")
+	sql, args = pgsql.Build(a)
+	assert.Equal(t, "insert into people (age, name) values ($1,$2) on conflict on constraint people_name_key do nothing returning id", sql)
+	assert.Equal(t, []interface{}{30, "Alice"}, args)
+}
+
+func TestInsertStatementOnConflictDoUpdate(t *testing.T) {
+	a := pgsql.Insert("people")
+	a.Data(pgsql.RowMap{"name": "Alice", "age": 30})
+	a.OnConflict("name").DoUpdate(pgsql.Excluded("age"))
+	a.Returning("id")
+	sql, args := pgsql.Build(a)
+	assert.Equal(t, "insert into people (age, name) values ($1,$2) on conflict (name) do update set age = excluded.age returning id", sql)
+	assert.Equal(t, []interface{}{30, "Alice"}, args)
+
+	a.OnConflict("name").OnConflictWhere("deleted_at is null").
+		DoUpdate(pgsql.RowMap{"age": 31, "updated_at": "now"}).
+		DoUpdateWhere("people.locked = ?", false)
+	sql, args = pgsql.Build(a)
+	assert.Equal(t, "insert into people (age, name) values ($1,$2) on conflict (name) where (deleted_at is null) do update set age = $3, updated_at = $4 where (people.locked = $5) returning id", sql)
+	assert.Equal(t, []interface{}{30, "Alice", 31, "now", false}, args)
+}
+
+func TestInsertStatementOnConflictWhereErrors(t *testing.T) {
+	tests := []*pgsql.InsertStatement{
+		pgsql.Insert("people").DefaultValues().OnConflictOnConstraint("people_name_key").OnConflictWhere("deleted_at is null").DoNothing(),
+		pgsql.Insert("people").DefaultValues().OnConflict().OnConflictWhere("deleted_at is null").DoNothing(),
+	}
+	for i, is := range tests {
+		_, _, err := pgsql.BuildE(is)
+		assert.EqualErrorf(t, err, "pgsql: OnConflictWhere requires conflict columns", "%d", i)
+	}
+
+	is := pgsql.Insert("people").DefaultValues().OnConflictWhere("deleted_at is null").OnConflict("name").DoNothing()
+	_, _, err := pgsql.BuildE(is)
+	assert.EqualError(t, err, "pgsql: OnConflictWhere requires a preceding OnConflict")
+}
+
+func TestExcluded(t *testing.T) {
+	a := pgsql.Update("people").Set(pgsql.Excluded("name", "age"))
+	sql, args := pgsql.Build(a)
+	assert.Equal(t, "update people set name = excluded.name, age = excluded.age", sql)
+	assert.Empty(t, args)
+}
+
+func TestInsertStatementSelect(t *testing.T) {
+	a := pgsql.Insert("archived_people").Columns("name", "age")
+	a.Select(pgsql.Select("name, age").From("people").Where("age > ?", 90))
+	a.Returning("id, ? as batch", 7)
+	sql, args := pgsql.Build(a)
+	assert.Equal(t, "insert into archived_people (name, age) select name, age from people where (age > $1) returning id, $2 as batch", sql)
+	assert.Equal(t, []interface{}{90, 7}, args)
+}
+
+func TestInsertStatementDefaultValues(t *testing.T) {
+	a := pgsql.Insert("people").DefaultValues().Returning("id")
+	sql, args := pgsql.Build(a)
+	assert.Equal(t, "insert into people default values returning id", sql)
+	assert.Empty(t, args)
+}
+
+func TestInsertStatementOverriding(t *testing.T) {
+	a := pgsql.Insert("people").Data(pgsql.RowMap{"id": 1, "name": "Alice"}).OverridingSystemValue()
+	sql, args := pgsql.Build(a)
+	assert.Equal(t, "insert into people (id, name) overriding system value values ($1,$2)", sql)
+	assert.Equal(t, []interface{}{1, "Alice"}, args)
+
+	a.OverridingUserValue()
+	sql, args = pgsql.Build(a)
+	assert.Equal(t, "insert into people (id, name) overriding user value values ($1,$2)", sql)
+	assert.Equal(t, []interface{}{1, "Alice"}, args)
+}
+
+func TestInsertStatementCloneCopiesNestedStatements(t *testing.T) {
+	vs := pgsql.Values().Row(1, "a")
+	base := pgsql.Insert("t").Columns("id", "n").Values(vs).OnConflict("id").DoUpdate(pgsql.Excluded("n"))
+	c := base.Clone().Returning("id")
+	vs.Row(2, "b")
+
+	sql, args := pgsql.Build(c)
+	assert.Equal(t, "insert into t (id, n) values ($1,$2) on conflict (id) do update set n = excluded.n returning id", sql)
+	assert.Equal(t, []interface{}{1, "a"}, args)
+
+	sql, _ = pgsql.Build(base)
+	assert.Equal(t, "insert into t (id, n) values ($1,$2), ($3,$4) on conflict (id) do update set n = excluded.n", sql)
+}
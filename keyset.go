@@ -0,0 +1,227 @@
+package pgsql
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// SortKey is a column used to order and page results with SelectStatement.Keyset.
+type SortKey struct {
+	Column string
+	Desc   bool
+
+	// Nullable must be set when Column may contain nulls. Nulls are sorted last regardless of direction.
+	Nullable bool
+}
+
+// Asc returns an ascending sort key for column.
+func Asc(column string) SortKey {
+	return SortKey{Column: column}
+}
+
+// Desc returns a descending sort key for column.
+func Desc(column string) SortKey {
+	return SortKey{Column: column, Desc: true}
+}
+
+func (k SortKey) WriteSQL(sb *strings.Builder, args *Args) {
+	writeName(sb, args, k.Column)
+	if k.Desc {
+		sb.WriteString(" desc")
+	} else {
+		sb.WriteString(" asc")
+	}
+	if k.Nullable {
+		sb.WriteString(" nulls last")
+	}
+}
+
+// Keyset pages the results by keys. It replaces the order by clause with keys, sets the limit to limit and, if cursor
+// is not empty, adds a where condition that selects the rows after the row whose key values are cursor. cursor is
+// typically decoded with DecodeCursor from a token created with EncodeCursor from the last row of the previous page.
+//
+// When all keys have the same direction and none is nullable the condition is a row comparison such as
+// "(created_at, id) < ($1, $2)" which can use a matching index. Otherwise it is expanded into an or of and conditions.
+// The last key should be unique so the order is total.
+func (ss *SelectStatement) Keyset(keys []SortKey, cursor []interface{}, limit int64) *SelectStatement {
+	ss = ss.mutable()
+	ss.orderByList = make([]SQLWriter, len(keys))
+	for i, k := range keys {
+		ss.orderByList[i] = k
+	}
+	ss.replaceOrderBy = true
+	ss.limit = limit
+
+	if len(cursor) > 0 {
+		ss.whereList = append(ss.whereList, &keysetCondition{keys: keys, values: cursor})
+	}
+
+	return ss
+}
+
+type keysetCondition struct {
+	keys   []SortKey
+	values []interface{}
+}
+
+func (kc *keysetCondition) WriteSQL(sb *strings.Builder, args *Args) {
+	if len(kc.keys) == 0 || len(kc.keys) != len(kc.values) {
+		args.SetError(fmt.Errorf("pgsql: keyset has %d keys but the cursor has %d values", len(kc.keys), len(kc.values)))
+		return
+	}
+
+	for i, k := range kc.keys {
+		if !k.Nullable && isNullValue(kc.values[i]) {
+			args.SetError(fmt.Errorf("pgsql: keyset cursor value for %s is null but it is not nullable", k.Column))
+			return
+		}
+	}
+
+	if kc.isRowComparable() {
+		kc.writeRowComparison(sb, args)
+		return
+	}
+
+	// Expand to: (k1 after v1) or (k1 = v1 and k2 after v2) or ...
+	var terms []SQLWriter
+	for i, k := range kc.keys {
+		after := keyAfter(k, kc.values[i])
+		if after == nil {
+			continue
+		}
+
+		conds := make([]SQLWriter, 0, i+1)
+		for j := 0; j < i; j++ {
+			conds = append(conds, keyEqual(kc.keys[j], kc.values[j]))
+		}
+		conds = append(conds, after)
+		terms = append(terms, And(conds...))
+	}
+
+	Or(terms...).WriteSQL(sb, args)
+}
+
+// isParenthesized reports true unless the condition is written as a row comparison of multiple keys. The expanded form
+// is built from expressions that write their own parentheses.
+func (kc *keysetCondition) isParenthesized() bool {
+	return !kc.isRowComparable() || len(kc.keys) == 1
+}
+
+// isRowComparable reports whether the condition can be written as a single row comparison.
+func (kc *keysetCondition) isRowComparable() bool {
+	for _, k := range kc.keys {
+		if k.Nullable || k.Desc != kc.keys[0].Desc {
+			return false
+		}
+	}
+	return true
+}
+
+func (kc *keysetCondition) writeRowComparison(sb *strings.Builder, args *Args) {
+	if len(kc.keys) == 1 {
+		keyAfter(kc.keys[0], kc.values[0]).WriteSQL(sb, args)
+		return
+	}
+
+	sb.WriteByte('(')
+	for i, k := range kc.keys {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		writeName(sb, args, k.Column)
+	}
+	if kc.keys[0].Desc {
+		sb.WriteString(") < (")
+	} else {
+		sb.WriteString(") > (")
+	}
+	for i, v := range kc.values {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		toSQLWriter(v).WriteSQL(sb, args)
+	}
+	sb.WriteByte(')')
+}
+
+// keyAfter returns a condition that is true for values of k that sort after v. It returns nil if no value sorts after
+// v. Nulls sort last.
+func keyAfter(k SortKey, v interface{}) SQLWriter {
+	if k.Nullable && isNullValue(v) {
+		return nil
+	}
+
+	var after SQLWriter
+	if k.Desc {
+		after = Lt(Col(k.Column), v)
+	} else {
+		after = Gt(Col(k.Column), v)
+	}
+
+	if k.Nullable {
+		return Or(after, IsNull(Col(k.Column)))
+	}
+	return after
+}
+
+func keyEqual(k SortKey, v interface{}) SQLWriter {
+	if k.Nullable && isNullValue(v) {
+		return IsNull(Col(k.Column))
+	}
+	return Eq(Col(k.Column), v)
+}
+
+// isNullValue reports whether v is nil or a nil pointer.
+func isNullValue(v interface{}) bool {
+	if v == nil {
+		return true
+	}
+	rv := reflect.ValueOf(v)
+	return rv.Kind() == reflect.Ptr && rv.IsNil()
+}
+
+// EncodeCursor returns an opaque token for the key values of the last row of a page. It is the base64url encoding of
+// the values as a JSON array.
+func EncodeCursor(values ...interface{}) (string, error) {
+	buf, err := json.Marshal(values)
+	if err != nil {
+		return "", fmt.Errorf("pgsql: encode cursor: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// DecodeCursor decodes token created by EncodeCursor into dest. dest must contain one pointer per encoded value. An
+// empty token decodes to no values and leaves dest unchanged.
+func DecodeCursor(token string, dest ...interface{}) error {
+	if token == "" {
+		return nil
+	}
+
+	buf, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return fmt.Errorf("pgsql: decode cursor: %w", err)
+	}
+
+	var values []json.RawMessage
+	err = json.Unmarshal(buf, &values)
+	if err != nil {
+		return fmt.Errorf("pgsql: decode cursor: %w", err)
+	}
+
+	if len(values) != len(dest) {
+		return errors.New("pgsql: decode cursor: cursor does not match the destinations")
+	}
+
+	for i, v := range values {
+		err = json.Unmarshal(v, dest[i])
+		if err != nil {
+			return fmt.Errorf("pgsql: decode cursor: %w", err)
+		}
+	}
+
+	return nil
+}
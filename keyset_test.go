@@ -0,0 +1,99 @@
+package pgsql_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jackc/pgsql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSelectStatementKeysetRowComparison(t *testing.T) {
+	ts := time.Date(2022, 3, 1, 0, 0, 0, 0, time.UTC)
+	ss := pgsql.Select("*").From("events").Where("account_id = ?", 9).Order("ignored").
+		Keyset([]pgsql.SortKey{pgsql.Desc("created_at"), pgsql.Desc("id")}, []interface{}{ts, 42}, 50)
+
+	sql, args := pgsql.Build(ss)
+	assert.Equal(t, "select * from events where (account_id = $1) and ((created_at, id) < ($2, $3)) order by created_at desc, id desc limit 50", sql)
+	assert.Equal(t, []interface{}{9, ts, 42}, args)
+}
+
+func TestSelectStatementKeysetFirstPage(t *testing.T) {
+	ss := pgsql.Select("*").From("events").Keyset([]pgsql.SortKey{pgsql.Asc("id")}, nil, 10)
+
+	sql, args := pgsql.Build(ss)
+	assert.Equal(t, "select * from events order by id asc limit 10", sql)
+	assert.Empty(t, args)
+}
+
+func TestSelectStatementKeysetSingleKey(t *testing.T) {
+	ss := pgsql.Select("*").From("events").Keyset([]pgsql.SortKey{pgsql.Asc("id")}, []interface{}{7}, 10)
+
+	sql, args := pgsql.Build(ss)
+	assert.Equal(t, "select * from events where (id > $1) order by id asc limit 10", sql)
+	assert.Equal(t, []interface{}{7}, args)
+}
+
+func TestSelectStatementKeysetMixedDirections(t *testing.T) {
+	ss := pgsql.Select("*").From("products").
+		Keyset([]pgsql.SortKey{pgsql.Desc("price"), pgsql.Asc("name"), pgsql.Asc("id")}, []interface{}{10, "b", 3}, 20)
+
+	sql, args := pgsql.Build(ss)
+	assert.Equal(t, "select * from products where ((price < $1) or ((price = $2) and (name > $3)) or ((price = $4) and (name = $5) and (id > $6))) order by price desc, name asc, id asc limit 20", sql)
+	assert.Equal(t, []interface{}{10, 10, "b", 10, "b", 3}, args)
+}
+
+func TestSelectStatementKeysetNullable(t *testing.T) {
+	keys := []pgsql.SortKey{{Column: "due_at", Nullable: true}, pgsql.Asc("id")}
+
+	ss := pgsql.Select("*").From("tasks").Keyset(keys, []interface{}{"2022-01-01", 5}, 20)
+	sql, args := pgsql.Build(ss)
+	assert.Equal(t, "select * from tasks where (((due_at > $1) or (due_at is null)) or ((due_at = $2) and (id > $3))) order by due_at asc nulls last, id asc limit 20", sql)
+	assert.Equal(t, []interface{}{"2022-01-01", "2022-01-01", 5}, args)
+
+	var dueAt *string
+	ss = pgsql.Select("*").From("tasks").Keyset(keys, []interface{}{dueAt, 5}, 20)
+	sql, args = pgsql.Build(ss)
+	assert.Equal(t, "select * from tasks where ((due_at is null) and (id > $1)) order by due_at asc nulls last, id asc limit 20", sql)
+	assert.Equal(t, []interface{}{5}, args)
+}
+
+func TestSelectStatementKeysetErrors(t *testing.T) {
+	_, _, err := pgsql.BuildE(pgsql.Select("*").Keyset([]pgsql.SortKey{pgsql.Asc("a"), pgsql.Asc("b")}, []interface{}{1}, 10))
+	assert.EqualError(t, err, "pgsql: keyset has 2 keys but the cursor has 1 values")
+
+	_, _, err = pgsql.BuildE(pgsql.Select("*").Keyset([]pgsql.SortKey{pgsql.Asc("a")}, []interface{}{nil}, 10))
+	assert.EqualError(t, err, "pgsql: keyset cursor value for a is null but it is not nullable")
+}
+
+func TestCursorRoundTrip(t *testing.T) {
+	ts := time.Date(2022, 3, 1, 12, 30, 0, 0, time.UTC)
+	token, err := pgsql.EncodeCursor(ts, int64(42), nil)
+	require.NoError(t, err)
+	assert.NotContains(t, token, "=")
+
+	var gotTS time.Time
+	var gotID int64
+	gotName := new(string)
+	err = pgsql.DecodeCursor(token, &gotTS, &gotID, &gotName)
+	require.NoError(t, err)
+	assert.True(t, ts.Equal(gotTS))
+	assert.Equal(t, int64(42), gotID)
+	assert.Nil(t, gotName)
+}
+
+func TestDecodeCursorErrors(t *testing.T) {
+	var id int64
+	require.NoError(t, pgsql.DecodeCursor("", &id))
+
+	assert.Error(t, pgsql.DecodeCursor("!!!", &id))
+
+	token, err := pgsql.EncodeCursor(1, 2)
+	require.NoError(t, err)
+	assert.EqualError(t, pgsql.DecodeCursor(token, &id), "pgsql: decode cursor: cursor does not match the destinations")
+
+	token, err = pgsql.EncodeCursor("x")
+	require.NoError(t, err)
+	assert.Error(t, pgsql.DecodeCursor(token, &id))
+}
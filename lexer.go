@@ -0,0 +1,131 @@
+package pgsql
+
+import (
+	"strings"
+)
+
+// indexMarker returns the index of the first byte in s that is one of markers and that is not inside a string
+// literal, quoted identifier, dollar quoted string or comment. It returns -1 if there is no such byte.
+func indexMarker(s, markers string) int {
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch {
+		case strings.IndexByte(markers, c) != -1:
+			return i
+		case c == '\'':
+			// An E before the quote starts an escape string where backslash escapes the next character. The E must not
+			// be the end of an identifier.
+			backslashEscapes := i > 0 && (s[i-1] == 'E' || s[i-1] == 'e') && (i == 1 || !isIdentByte(s[i-2]))
+			i = skipQuoted(s, i+1, '\'', backslashEscapes)
+		case c == '"':
+			i = skipQuoted(s, i+1, '"', false)
+		case c == '-' && i+1 < len(s) && s[i+1] == '-':
+			n := strings.IndexByte(s[i:], '\n')
+			if n == -1 {
+				return -1
+			}
+			i += n
+		case c == '/' && i+1 < len(s) && s[i+1] == '*':
+			i = skipBlockComment(s, i+2)
+		case c == '$' && (i == 0 || !isIdentByte(s[i-1])):
+			tag := dollarQuoteTag(s[i:])
+			if tag == "" {
+				i++
+				continue
+			}
+			n := strings.Index(s[i+len(tag):], tag)
+			if n == -1 {
+				return -1
+			}
+			i += len(tag) + n + len(tag)
+		default:
+			i++
+		}
+	}
+
+	return -1
+}
+
+// skipQuoted returns the index after the closing quote of a string that began before i. A doubled quote is an escaped
+// quote.
+func skipQuoted(s string, i int, quote byte, backslashEscapes bool) int {
+	for i < len(s) {
+		switch s[i] {
+		case '\\':
+			if backslashEscapes {
+				i += 2
+				continue
+			}
+		case quote:
+			if i+1 < len(s) && s[i+1] == quote {
+				i += 2
+				continue
+			}
+			return i + 1
+		}
+		i++
+	}
+
+	return len(s)
+}
+
+// skipBlockComment returns the index after the end of a block comment that began before i. Block comments may be
+// nested.
+func skipBlockComment(s string, i int) int {
+	depth := 1
+	for i < len(s) {
+		if s[i] == '/' && i+1 < len(s) && s[i+1] == '*' {
+			depth++
+			i += 2
+		} else if s[i] == '*' && i+1 < len(s) && s[i+1] == '/' {
+			depth--
+			i += 2
+			if depth == 0 {
+				return i
+			}
+		} else {
+			i++
+		}
+	}
+
+	return len(s)
+}
+
+// dollarQuoteTag returns the dollar quote tag such as $$ or $body$ at the start of s. It returns "" if s does not start
+// with a tag. e.g. $1 is a positional parameter, not a tag.
+func dollarQuoteTag(s string) string {
+	for i := 1; i < len(s); i++ {
+		c := s[i]
+		if c == '$' {
+			return s[:i+1]
+		}
+		if !isIdentByte(c) || c == '$' || (i == 1 && '0' <= c && c <= '9') {
+			return ""
+		}
+	}
+
+	return ""
+}
+
+// countPlaceholders returns the number of ? placeholders in s as interpreted by Args.Format.
+func countPlaceholders(s string) int {
+	n := 0
+	for {
+		pos := indexMarker(s, "?")
+		if pos == -1 {
+			return n
+		}
+
+		if pos+1 < len(s) && s[pos+1] == '?' {
+			s = s[pos+2:]
+			continue
+		}
+
+		n++
+		s = s[pos+1:]
+	}
+}
+
+func isIdentByte(c byte) bool {
+	return c == '_' || c == '$' || ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z') || ('0' <= c && c <= '9') || c >= 0x80
+}
@@ -0,0 +1,59 @@
+package pgsql_test
+
+import (
+	"testing"
+
+	"github.com/jackc/pgsql"
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestArgsFormatIgnoresQuestionMarksInLexicalElements(t *testing.T) {
+	tests := []struct {
+		s   string
+		sql string
+	}{
+		{`a = ? and b = 'what?'`, `a = $1 and b = 'what?'`},
+		{`a = 'it''s?' and b = ?`, `a = 'it''s?' and b = $1`},
+		{`a = E'\'?' and b = ?`, `a = E'\'?' and b = $1`},
+		{`a = e'\\' and b = ?`, `a = e'\\' and b = $1`},
+		{`"who?" = ?`, `"who?" = $1`},
+		{`"a""?" = ?`, `"a""?" = $1`},
+		{"a = ? -- why?\nand b = 1", "a = $1 -- why?\nand b = 1"},
+		{"a = ? -- why?", "a = $1 -- why?"},
+		{`a = ? /* why? /* nested? */ still? */`, `a = $1 /* why? /* nested? */ still? */`},
+		{`a = $$what?$$ and b = ?`, `a = $$what?$$ and b = $1`},
+		{`a = $body$ $$? $body$ and b = ?`, `a = $body$ $$? $body$ and b = $1`},
+		{`a$b = ? and c = $1`, `a$b = $1 and c = $1`},
+		{`data ?? 'key' and tags ??| ? and tags ??& array['a']`, `data ? 'key' and tags ?| $1 and tags ?& array['a']`},
+	}
+
+	for i, tt := range tests {
+		args := &pgsql.Args{}
+		sql := args.Format(tt.s, 1)
+		assert.Equalf(t, tt.sql, sql, "%d", i)
+		assert.NoErrorf(t, args.Err(), "%d", i)
+	}
+}
+
+func TestArgsFormatUnterminatedLexicalElements(t *testing.T) {
+	for _, s := range []string{`'?`, `"?`, `/* ?`, `$$ ?`, `-- ?`} {
+		args := &pgsql.Args{}
+		assert.Equal(t, s, args.Format(s))
+		assert.NoError(t, args.Err())
+	}
+}
+
+func TestArgsFormatNamedIgnoresMarkersInLexicalElements(t *testing.T) {
+	args := &pgsql.Args{}
+	sql := args.Format(`a = :a and b = ':a' and c = "@a" and d = $$:a$$ /* @a */ and e = @a -- :a`, pgx.NamedArgs{"a": 1})
+	assert.Equal(t, `a = $1 and b = ':a' and c = "@a" and d = $$:a$$ /* @a */ and e = $1 -- :a`, sql)
+	assert.Equal(t, []interface{}{1}, args.Values())
+}
+
+func TestJSONBOperatorsInStatement(t *testing.T) {
+	a := pgsql.Select("*").From("widgets").Where("data ?? ?", "color").Where("data->'tags' ??| ?", []string{"a", "b"})
+	sql, args := pgsql.Build(a)
+	assert.Equal(t, "select * from widgets where (data ? $1) and (data->'tags' ?| $2)", sql)
+	assert.Equal(t, []interface{}{"color", []string{"a", "b"}}, args)
+}
@@ -0,0 +1,199 @@
+package pgsql
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"unicode"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// NamedArgs provides values for named markers in a format string. When a NamedArgs or a pgx.NamedArgs is the only
+// argument to a format string such as Where or Select, markers of the form :name and @name are replaced by
+// placeholders instead of ?. Each name is assigned a single placeholder no matter how many times it appears. A marker
+// whose name is not found causes an error when the statement is built, except for a :name that directly follows an
+// identifier, number, [ or ), which is written unchanged so array slices such as a[lo:hi] are unaffected.
+type NamedArgs interface {
+	Lookup(name string) (interface{}, bool)
+}
+
+// Named returns NamedArgs for v. v may be a map with string keys or a struct or pointer to a struct. Struct fields are
+// named by their db tag or by their field name matched case-insensitively. Any other type causes an error when the
+// statement is built.
+func Named(v interface{}) NamedArgs {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		return namedMap(v)
+	case pgx.NamedArgs:
+		return namedMap(v)
+	}
+
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Ptr && !rv.IsNil() {
+		rv = rv.Elem()
+	}
+
+	switch rv.Kind() {
+	case reflect.Map:
+		if rv.Type().Key().Kind() == reflect.String {
+			return namedReflectMap{m: rv}
+		}
+	case reflect.Struct:
+		return namedStruct{v: rv}
+	}
+
+	return namedError{err: fmt.Errorf("pgsql: Named requires a map with string keys or a struct, not %T", v)}
+}
+
+// namedError is returned by Named for an unsupported type. It finds no names. The error is reported when it is used to
+// format a string.
+type namedError struct {
+	err error
+}
+
+func (ne namedError) Lookup(name string) (interface{}, bool) {
+	return nil, false
+}
+
+type namedMap map[string]interface{}
+
+func (nm namedMap) Lookup(name string) (interface{}, bool) {
+	v, ok := nm[name]
+	return v, ok
+}
+
+type namedReflectMap struct {
+	m reflect.Value
+}
+
+func (nrm namedReflectMap) Lookup(name string) (interface{}, bool) {
+	v := nrm.m.MapIndex(reflect.ValueOf(name).Convert(nrm.m.Type().Key()))
+	if !v.IsValid() {
+		return nil, false
+	}
+	return v.Interface(), true
+}
+
+type namedStruct struct {
+	v reflect.Value
+}
+
+func (ns namedStruct) Lookup(name string) (interface{}, bool) {
+	sf, ok := getStructInfo(ns.v.Type()).lookup(name)
+	if !ok {
+		return nil, false
+	}
+	return ns.v.FieldByIndex(sf.index).Interface(), true
+}
+
+// asNamedArgs returns the NamedArgs for values if it is a single NamedArgs or pgx.NamedArgs.
+func asNamedArgs(values []interface{}) (NamedArgs, bool) {
+	if len(values) != 1 {
+		return nil, false
+	}
+
+	switch v := values[0].(type) {
+	case NamedArgs:
+		return v, true
+	case pgx.NamedArgs:
+		return namedMap(v), true
+	}
+
+	return nil, false
+}
+
+func (a *Args) formatNamed(s string, na NamedArgs) string {
+	if ne, ok := na.(namedError); ok {
+		a.SetError(ne.err)
+	}
+
+	original := s
+
+	b := &strings.Builder{}
+	var placeholders map[string]Placeholder
+
+	for {
+		pos := indexMarker(s, ":@?")
+		if pos == -1 {
+			b.WriteString(s)
+			break
+		}
+
+		b.WriteString(s[:pos])
+
+		// ?? is a literal ? as in positional mode. A single ? has no special meaning.
+		if s[pos] == '?' {
+			b.WriteByte('?')
+			if pos+1 < len(s) && s[pos+1] == '?' {
+				pos++
+			}
+			s = s[pos+1:]
+			continue
+		}
+
+		// :: is a type cast.
+		if s[pos] == ':' && pos+1 < len(s) && s[pos+1] == ':' {
+			b.WriteString("::")
+			s = s[pos+2:]
+			continue
+		}
+
+		n := namedMarkerLen(s[pos+1:])
+		name := s[pos+1 : pos+1+n]
+		if n > 0 {
+			if p, ok := placeholders[name]; ok {
+				b.WriteString(p.String())
+				s = s[pos+1+n:]
+				continue
+			}
+
+			if v, ok := na.Lookup(name); ok {
+				if _, ok := v.(SQLWriter); ok {
+					a.writeArg(b, v)
+					s = s[pos+1+n:]
+					continue
+				}
+
+				if placeholders == nil {
+					placeholders = make(map[string]Placeholder)
+				}
+				p := a.Use(v)
+				placeholders[name] = p
+				b.WriteString(p.String())
+				s = s[pos+1+n:]
+				continue
+			}
+
+			if s[pos] == '@' || !isSliceBound(original, len(original)-len(s)+pos) {
+				a.SetError(fmt.Errorf("pgsql: %q has no value for %s", original, s[pos:pos+1+n]))
+			}
+		}
+
+		b.WriteString(s[pos : pos+1+n])
+		s = s[pos+1+n:]
+	}
+
+	return b.String()
+}
+
+// isSliceBound reports whether the : at s[i] may separate array slice bounds such as a[lo:hi] rather than start a named
+// marker.
+func isSliceBound(s string, i int) bool {
+	if i == 0 {
+		return false
+	}
+	prev := s[i-1]
+	return isIdentByte(prev) || prev == '[' || prev == ')'
+}
+
+// namedMarkerLen returns the length of the identifier at the start of s.
+func namedMarkerLen(s string) int {
+	for i, r := range s {
+		if r == '_' || unicode.IsLetter(r) || (i > 0 && unicode.IsDigit(r)) {
+			continue
+		}
+		return i
+	}
+	return len(s)
+}
@@ -0,0 +1,79 @@
+package pgsql_test
+
+import (
+	"testing"
+
+	"github.com/jackc/pgsql"
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestArgsFormatNamed(t *testing.T) {
+	args := &pgsql.Args{}
+	args.Use("x")
+
+	s := args.Format("tenant_id = :tenant_id and (owner_id = @owner or creator_id = @owner) and parent_id = :tenant_id", pgsql.Named(map[string]interface{}{"tenant_id": 7, "owner": 8}))
+	assert.Equal(t, "tenant_id = $2 and (owner_id = $3 or creator_id = $3) and parent_id = $2", s)
+	assert.Equal(t, []interface{}{"x", 7, 8}, args.Values())
+}
+
+func TestArgsFormatNamedPassesThroughUnknownMarkers(t *testing.T) {
+	args := &pgsql.Args{}
+
+	s := args.Format("a[lo:hi]::int[] @> array[:v]::int[] and b[:hi] = b[1:hi] and c = ':' and d := @ 5", pgx.NamedArgs{"v": 1})
+	assert.Equal(t, "a[lo:hi]::int[] @> array[$1]::int[] and b[:hi] = b[1:hi] and c = ':' and d := @ 5", s)
+	assert.Equal(t, []interface{}{1}, args.Values())
+	assert.NoError(t, args.Err())
+}
+
+func TestArgsFormatNamedEscapedQuestionMark(t *testing.T) {
+	sql, args := pgsql.Build(pgsql.Select("*").From("t").Where("data ?? :k and tags ??| :tags and x ? y", pgsql.Named(map[string]interface{}{"k": "a", "tags": []string{"b"}})))
+	assert.Equal(t, "select * from t where (data ? $1 and tags ?| $2 and x ? y)", sql)
+	assert.Equal(t, []interface{}{"a", []string{"b"}}, args)
+}
+
+func TestArgsFormatNamedUnknownMarker(t *testing.T) {
+	_, _, err := pgsql.BuildE(pgsql.Select("*").From("t").Where("id = :tenat_id", pgsql.Named(map[string]interface{}{"tenant_id": 1})))
+	assert.EqualError(t, err, `pgsql: "id = :tenat_id" has no value for :tenat_id`)
+
+	_, _, err = pgsql.BuildE(pgsql.Select("*").From("t").Where("a[x:y] = 1 and b = @since", pgx.NamedArgs{"until": 1}))
+	assert.EqualError(t, err, `pgsql: "a[x:y] = 1 and b = @since" has no value for @since`)
+}
+
+func TestArgsFormatNamedStruct(t *testing.T) {
+	type params struct {
+		TenantID int64 `db:"tenant_id"`
+		Since    string
+		Ignored  string `db:"-"`
+	}
+
+	args := &pgsql.Args{}
+	s := args.Format("tenant_id = :tenant_id and created_at > :since and x = :ignored and y = :TenantID", pgsql.Named(&params{TenantID: 7, Since: "2022-01-01"}))
+	assert.Equal(t, "tenant_id = $1 and created_at > $2 and x = :ignored and y = :TenantID", s)
+	assert.Equal(t, []interface{}{int64(7), "2022-01-01"}, args.Values())
+}
+
+func TestNamedArgsInStatements(t *testing.T) {
+	params := pgx.NamedArgs{"tenant_id": 7, "since": "2022-01-01"}
+
+	a := pgsql.Select("count(*) filter (where created_at > @since)", params).
+		From("orders").
+		Where("tenant_id = @tenant_id and created_at > @since", params)
+	sql, args := pgsql.Build(a)
+	assert.Equal(t, "select count(*) filter (where created_at > $1) from orders where (tenant_id = $2 and created_at > $3)", sql)
+	assert.Equal(t, []interface{}{"2022-01-01", 7, "2022-01-01"}, args)
+
+	sql, args = pgsql.Build(a, pgsql.Deduplicate())
+	assert.Equal(t, "select count(*) filter (where created_at > $1) from orders where (tenant_id = $2 and created_at > $1)", sql)
+	assert.Equal(t, []interface{}{"2022-01-01", 7}, args)
+
+	u := pgsql.Update("people").Setf("name = :name", pgsql.Named(map[string]interface{}{"name": "Alice"})).Returning("id, :tag as tag", pgx.NamedArgs{"tag": "x"})
+	sql, args = pgsql.Build(u)
+	assert.Equal(t, "update people set name = $1 returning id, $2 as tag", sql)
+	assert.Equal(t, []interface{}{"Alice", "x"}, args)
+}
+
+func TestNamedRejectsUnsupportedType(t *testing.T) {
+	_, _, err := pgsql.BuildE(pgsql.Select("*").Where("a = :a", pgsql.Named(42)))
+	assert.EqualError(t, err, "pgsql: Named requires a map with string keys or a struct, not int")
+}
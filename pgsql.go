@@ -2,6 +2,9 @@
 package pgsql
 
 import (
+	"errors"
+	"fmt"
+	"reflect"
 	"sort"
 	"strings"
 
@@ -32,29 +35,54 @@ func (p Param) WriteSQL(sb *strings.Builder, args *Args) {
 	sb.WriteString(args.Use(p.Value).String())
 }
 
-func Build(ab SQLWriter) (string, []interface{}) {
+// Build returns the SQL and arguments for ab. Errors are ignored so the SQL may be invalid. Use BuildE to detect errors
+// such as a mismatch between placeholders and arguments.
+func Build(ab SQLWriter, options ...ArgsOption) (string, []interface{}) {
+	sql, values, _ := build(ab, options)
+	return sql, values
+}
+
+// BuildE returns the SQL and arguments for ab. Any error reported to Args while writing is returned.
+func BuildE(ab SQLWriter, options ...ArgsOption) (string, []interface{}, error) {
+	sql, values, err := build(ab, options)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return sql, values, nil
+}
+
+func build(ab SQLWriter, options []ArgsOption) (string, []interface{}, error) {
+	if isNilSQLWriter(ab) {
+		return "", nil, errors.New("pgsql: cannot build nil statement")
+	}
+
 	sb := &strings.Builder{}
-	args := &Args{}
+	args := NewArgs(options...)
 
 	ab.WriteSQL(sb, args)
 
-	return sb.String(), args.Values()
+	return sb.String(), args.Values(), args.Err()
 }
 
-type binaryExpr struct {
-	left  SQLWriter
-	op    string
-	right SQLWriter
+// isNilSQLWriter reports whether w is nil or a nil pointer.
+func isNilSQLWriter(w SQLWriter) bool {
+	if w == nil {
+		return true
+	}
+
+	v := reflect.ValueOf(w)
+	return v.Kind() == reflect.Ptr && v.IsNil()
+}
+
+// errorWriter reports err when written. It allows builder methods to surface invalid arguments when the statement is
+// built.
+type errorWriter struct {
+	err error
 }
 
-func (be *binaryExpr) WriteSQL(sb *strings.Builder, args *Args) {
-	sb.WriteByte('(')
-	be.left.WriteSQL(sb, args)
-	sb.WriteByte(' ')
-	sb.WriteString(be.op)
-	sb.WriteByte(' ')
-	be.right.WriteSQL(sb, args)
-	sb.WriteByte(')')
+func (ew *errorWriter) WriteSQL(sb *strings.Builder, args *Args) {
+	args.SetError(ew.err)
 }
 
 type RowMap map[string]interface{}
@@ -78,7 +106,7 @@ func (rm RowMap) UpdateData() []*Assignment {
 
 	assignments := make([]*Assignment, len(keys))
 	for i, k := range keys {
-		assignments[i] = &Assignment{Left: rawSQL(k), Right: &Param{Value: rm[k]}}
+		assignments[i] = &Assignment{Left: Column(k), Right: &Param{Value: rm[k]}}
 	}
 
 	return assignments
@@ -95,6 +123,44 @@ func (rm RowMap) sortedKeys() []string {
 	return keys
 }
 
+// cloneSlice returns a copy of s that does not share its backing array.
+func cloneSlice[S ~[]E, E any](s S) S {
+	if s == nil {
+		return nil
+	}
+	return append(make(S, 0, len(s)), s...)
+}
+
+// cloneStatement returns a copy of w if it is a statement or subquery built by this package so that nested statements
+// are not shared between clones. Other SQLWriters are returned unchanged.
+func cloneStatement(w SQLWriter) SQLWriter {
+	if isNilSQLWriter(w) {
+		return w
+	}
+
+	switch w := w.(type) {
+	case *SelectStatement:
+		return w.Clone()
+	case *InsertStatement:
+		return w.Clone()
+	case *UpdateStatement:
+		return w.Clone()
+	case *DeleteStatement:
+		return w.Clone()
+	case *ValuesStatement:
+		return w.Clone()
+	case *CompoundStatement:
+		return w.Clone()
+	case *Subquery:
+		c := *w
+		c.stmt = cloneStatement(w.stmt)
+		c.columns = cloneSlice(w.columns)
+		return &c
+	default:
+		return w
+	}
+}
+
 type FormatString struct {
 	s    string
 	args []interface{}
@@ -104,19 +170,61 @@ func (fs *FormatString) WriteSQL(sb *strings.Builder, args *Args) {
 	sb.WriteString(args.Format(fs.s, fs.args...))
 }
 
+// checkExpr returns w or a SQLWriter that reports an error if w is nil. kind describes w in the error message.
+func checkExpr(kind string, w SQLWriter) SQLWriter {
+	if isNilSQLWriter(w) {
+		return &errorWriter{err: fmt.Errorf("pgsql: %s is nil", kind)}
+	}
+	return w
+}
+
+// newExpr returns a SQLWriter for v which may be a format string with args or a SQLWriter. kind describes v in error
+// messages.
+func newExpr(kind string, v interface{}, args []interface{}) SQLWriter {
+	switch v := v.(type) {
+	case string:
+		return &FormatString{s: v, args: args}
+	case SQLWriter:
+		if isNilSQLWriter(v) {
+			return &errorWriter{err: fmt.Errorf("pgsql: %s is nil", kind)}
+		}
+		if len(args) > 0 {
+			return &errorWriter{err: fmt.Errorf("pgsql: args are not allowed with a %T %s", v, kind)}
+		}
+		return v
+	default:
+		return &errorWriter{err: fmt.Errorf("pgsql: unsupported %s type %T", kind, v)}
+	}
+}
+
 type whereList []SQLWriter
 
 func (wl whereList) WriteSQL(sb *strings.Builder, args *Args) {
-	if len(wl) == 0 {
+	writeConditionList(sb, args, " where ", wl)
+}
+
+type havingList []SQLWriter
+
+func (hl havingList) WriteSQL(sb *strings.Builder, args *Args) {
+	writeConditionList(sb, args, " having ", hl)
+}
+
+// writeConditionList writes keyword followed by conditions combined with and.
+func writeConditionList(sb *strings.Builder, args *Args, keyword string, conditions []SQLWriter) {
+	if len(conditions) == 0 {
 		return
 	}
 
-	sb.WriteString(" where ")
+	sb.WriteString(keyword)
 
-	for i, expr := range wl {
+	for i, expr := range conditions {
 		if i > 0 {
 			sb.WriteString(" and ")
 		}
+		if isParenthesized(expr) {
+			expr.WriteSQL(sb, args)
+			continue
+		}
 		sb.WriteByte('(')
 		expr.WriteSQL(sb, args)
 		sb.WriteByte(')')
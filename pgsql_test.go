@@ -0,0 +1,86 @@
+package pgsql_test
+
+import (
+	"testing"
+
+	"github.com/jackc/pgsql"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildE(t *testing.T) {
+	sql, args, err := pgsql.BuildE(pgsql.Select("*").From("people").Where("id = ?", 42))
+	assert.NoError(t, err)
+	assert.Equal(t, "select * from people where (id = $1)", sql)
+	assert.Equal(t, []interface{}{42}, args)
+}
+
+func TestBuildEArgCountMismatch(t *testing.T) {
+	_, _, err := pgsql.BuildE(pgsql.Select("*").From("people").Where("a = ? and b = ?", 1))
+	assert.EqualError(t, err, `pgsql: "a = ? and b = ?" has more placeholders than the 1 args provided`)
+
+	_, _, err = pgsql.BuildE(pgsql.Select("*").From("people").Where("a = ?", 1, 2))
+	assert.EqualError(t, err, `pgsql: "a = ?" has 1 placeholders but 2 args were provided`)
+}
+
+func TestBuildEFirstErrorWins(t *testing.T) {
+	_, _, err := pgsql.BuildE(pgsql.Select("?").Where("a = ?", 1, 2))
+	assert.EqualError(t, err, `pgsql: "?" has more placeholders than the 0 args provided`)
+}
+
+func TestBuildENilStatement(t *testing.T) {
+	_, _, err := pgsql.BuildE(nil)
+	assert.EqualError(t, err, "pgsql: cannot build nil statement")
+
+	var ss *pgsql.SelectStatement
+	_, _, err = pgsql.BuildE(ss)
+	assert.EqualError(t, err, "pgsql: cannot build nil statement")
+
+	_, _, err = pgsql.BuildE(pgsql.Insert("people").Select(nil))
+	assert.EqualError(t, err, "pgsql: insert select statement is nil")
+
+	_, _, err = pgsql.BuildE(pgsql.Select("*").From("t").With("x", ss))
+	assert.EqualError(t, err, `pgsql: common table expression "x" must have a name and query`)
+
+	_, _, err = pgsql.BuildE(pgsql.Union(pgsql.Select("1"), ss))
+	assert.EqualError(t, err, "pgsql: compound statement operand is nil")
+
+	_, _, err = pgsql.BuildE(pgsql.In(pgsql.Col("id"), ss))
+	assert.EqualError(t, err, "pgsql: in subquery is nil")
+}
+
+func TestBuildEInvalidClauses(t *testing.T) {
+	tests := []struct {
+		stmt pgsql.SQLWriter
+		err  string
+	}{
+		{pgsql.Select("*").From("t").Limit(-1), "pgsql: limit and offset must not be negative"},
+		{pgsql.Union(pgsql.Select("1")).Offset(-1), "pgsql: limit and offset must not be negative"},
+		{pgsql.Union(), "pgsql: compound statement has no operands"},
+		{pgsql.Select("*").From("t").SkipLocked(), "pgsql: SkipLocked requires a preceding locking clause such as ForUpdate"},
+		{pgsql.Select("*").From("t").Join("", "true"), "pgsql: join table is empty"},
+		{pgsql.Values(), "pgsql: values statement has no rows"},
+		{pgsql.Values().Row(1, 2).Row(3), "pgsql: values statement rows must have the same number of values"},
+		{pgsql.Update("people"), "pgsql: update statement has no assignments"},
+		{pgsql.Update("").Setf("a = 1"), "pgsql: update table name is empty"},
+		{pgsql.Delete(""), "pgsql: delete table name is empty"},
+		{pgsql.Insert("").DefaultValues(), "pgsql: insert table name is empty"},
+		{pgsql.Insert("t").DefaultValues().OnConflict("id"), "pgsql: on conflict requires DoNothing or DoUpdate with at least one assignment"},
+		{pgsql.Insert("t").DefaultValues().OnConflict().DoUpdate(pgsql.Excluded("a")), "pgsql: on conflict do update requires a conflict target"},
+	}
+
+	for i, tt := range tests {
+		_, _, err := pgsql.BuildE(tt.stmt)
+		assert.EqualErrorf(t, err, tt.err, "%d", i)
+	}
+}
+
+func TestBuildIgnoresErrors(t *testing.T) {
+	sql, args := pgsql.Build(pgsql.Select("*").From("people").Where("a = ?", 1, 2))
+	assert.Equal(t, "select * from people where (a = $1)", sql)
+	assert.Equal(t, []interface{}{1}, args)
+
+	assert.NotPanics(t, func() { pgsql.Build(pgsql.Update("people").Where("id = ?", 1)) })
+	assert.NotPanics(t, func() { pgsql.Build(pgsql.Values()) })
+	assert.NotPanics(t, func() { pgsql.Build(pgsql.Insert("people").DefaultValues().OnConflict()) })
+	assert.NotPanics(t, func() { pgsql.Build(nil) })
+}
@@ -0,0 +1,145 @@
+package pgsql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// SelectAll executes ss with db and scans all rows into a slice of T. T must be a struct. Columns are mapped to fields
+// by db tag. Fields without a db tag are matched by field name case-insensitively. If ss has no select list, the
+// columns of all fields with a db tag are selected. If ss has joins the generated columns are qualified with the table
+// name or alias of the from item, which must then be a table or an aliased subquery.
+func SelectAll[T any](ctx context.Context, db Querier, ss *SelectStatement, options ...ArgsOption) ([]T, error) {
+	var results []T
+	err := SelectIter(ctx, db, ss, func(v T) error {
+		results = append(results, v)
+		return nil
+	}, options...)
+	if err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// errStopIteration is returned by a SelectIter callback to stop reading rows without an error.
+var errStopIteration = errors.New("pgsql: stop iteration")
+
+// SelectOne executes ss with db and scans the first row into T. It returns pgx.ErrNoRows if there are no rows. If there
+// are multiple rows the rest are not read and no error is returned. ss should have an order by clause when the row
+// returned matters and a limit when the result may be large. See SelectAll for how columns are mapped.
+func SelectOne[T any](ctx context.Context, db Querier, ss *SelectStatement, options ...ArgsOption) (T, error) {
+	var result T
+	found := false
+	err := SelectIter(ctx, db, ss, func(v T) error {
+		result = v
+		found = true
+		return errStopIteration
+	}, options...)
+	if err != nil && err != errStopIteration {
+		return result, err
+	}
+	if !found {
+		return result, pgx.ErrNoRows
+	}
+
+	return result, nil
+}
+
+// SelectIter executes ss with db and calls fn with each row scanned into T. If fn returns an error iteration stops and
+// the error is returned. See SelectAll for how columns are mapped.
+func SelectIter[T any](ctx context.Context, db Querier, ss *SelectStatement, fn func(T) error, options ...ArgsOption) error {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	if t.Kind() != reflect.Struct {
+		return fmt.Errorf("pgsql: cannot scan into %v, it must be a struct", t)
+	}
+	si := getStructInfo(t)
+
+	if ss != nil && len(ss.selectList) == 0 {
+		columns := si.taggedColumns()
+		if len(columns) == 0 {
+			return fmt.Errorf("pgsql: cannot generate select list, %v has no fields with a db tag", t)
+		}
+
+		var qualifier string
+		if len(ss.joinList) > 0 {
+			var ok bool
+			qualifier, ok = fromQualifier(ss.from)
+			if !ok {
+				return errors.New("pgsql: cannot generate select list with joins unless from is a table or aliased subquery")
+			}
+			qualifier += "."
+		}
+
+		withSelect := *ss
+		withSelect.selectList = make([]SQLWriter, len(columns))
+		for i, c := range columns {
+			withSelect.selectList[i] = Column(qualifier + c)
+		}
+		ss = &withSelect
+	}
+
+	rows, err := Query(ctx, db, ss, options...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	fieldDescriptions := rows.FieldDescriptions()
+	fields := make([]*structField, len(fieldDescriptions))
+	for i, fd := range fieldDescriptions {
+		sf, ok := si.lookup(string(fd.Name))
+		if !ok {
+			return fmt.Errorf("pgsql: no field in %v for column %q", t, string(fd.Name))
+		}
+		fields[i] = sf
+	}
+
+	dest := make([]interface{}, len(fields))
+	for rows.Next() {
+		var v T
+		rv := reflect.ValueOf(&v).Elem()
+		for i, sf := range fields {
+			dest[i] = rv.FieldByIndex(sf.index).Addr().Interface()
+		}
+
+		err := rows.Scan(dest...)
+		if err != nil {
+			return err
+		}
+
+		err = fn(v)
+		if err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+// fromQualifier returns the table name or alias that refers to the from item. ok is false if from is not a table or an
+// aliased subquery.
+func fromQualifier(from SQLWriter) (qualifier string, ok bool) {
+	switch from := from.(type) {
+	case *FormatString:
+		fields := strings.Fields(from.s)
+		if len(from.args) > 0 || len(fields) == 0 || len(fields) > 3 {
+			return "", false
+		}
+		if len(fields) == 3 && !strings.EqualFold(fields[1], "as") {
+			return "", false
+		}
+		qualifier = fields[len(fields)-1]
+		_, ok = splitName(qualifier)
+		return qualifier, ok
+	case *Subquery:
+		return from.alias, from.alias != ""
+	default:
+		return "", false
+	}
+}
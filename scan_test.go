@@ -0,0 +1,177 @@
+package pgsql_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/jackc/pgsql"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgproto3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeRows is a pgx.Rows that returns canned values.
+type fakeRows struct {
+	columns []string
+	values  [][]interface{}
+	row     int
+	closed  bool
+}
+
+func newFakeRows(columns []string, values ...[]interface{}) *fakeRows {
+	return &fakeRows{columns: columns, values: values, row: -1}
+}
+
+func (r *fakeRows) Close()                        { r.closed = true }
+func (r *fakeRows) Err() error                    { return nil }
+func (r *fakeRows) CommandTag() pgconn.CommandTag { return pgconn.CommandTag{} }
+func (r *fakeRows) RawValues() [][]byte           { return nil }
+func (r *fakeRows) Conn() *pgx.Conn               { return nil }
+
+func (r *fakeRows) FieldDescriptions() []pgproto3.FieldDescription {
+	fds := make([]pgproto3.FieldDescription, len(r.columns))
+	for i, c := range r.columns {
+		fds[i].Name = []byte(c)
+	}
+	return fds
+}
+
+func (r *fakeRows) Next() bool {
+	r.row++
+	return r.row < len(r.values)
+}
+
+func (r *fakeRows) Scan(dest ...interface{}) error {
+	for i, d := range dest {
+		reflect.ValueOf(d).Elem().Set(reflect.ValueOf(r.values[r.row][i]))
+	}
+	return nil
+}
+
+func (r *fakeRows) Values() ([]interface{}, error) {
+	return r.values[r.row], nil
+}
+
+type scanTimestamps struct {
+	CreatedAt string `db:"created_at"`
+}
+
+type scanPerson struct {
+	ID   int32  `db:"id"`
+	Name string `db:"name"`
+	Age  int32
+	scanTimestamps
+	Ignored string `db:"-"`
+}
+
+func TestSelectAll(t *testing.T) {
+	db := &mockQuerier{rows: newFakeRows([]string{"id", "name", "age", "created_at"},
+		[]interface{}{int32(1), "Alice", int32(30), "2022-01-01"},
+		[]interface{}{int32(2), "Bob", int32(32), "2022-01-02"},
+	)}
+
+	people, err := pgsql.SelectAll[scanPerson](context.Background(), db, pgsql.Select("id, name, age, created_at").From("people").Where("age > ?", 18))
+	require.NoError(t, err)
+	assert.Equal(t, "select id, name, age, created_at from people where (age > $1)", db.sql)
+	assert.Equal(t, []interface{}{18}, db.args)
+	assert.Equal(t, []scanPerson{
+		{ID: 1, Name: "Alice", Age: 30, scanTimestamps: scanTimestamps{CreatedAt: "2022-01-01"}},
+		{ID: 2, Name: "Bob", Age: 32, scanTimestamps: scanTimestamps{CreatedAt: "2022-01-02"}},
+	}, people)
+	assert.True(t, db.rows.(*fakeRows).closed)
+}
+
+func TestSelectAllGeneratesSelectList(t *testing.T) {
+	db := &mockQuerier{rows: newFakeRows([]string{"id", "name", "created_at"})}
+
+	ss := pgsql.From("people").Where("age > ?", 18)
+	people, err := pgsql.SelectAll[scanPerson](context.Background(), db, ss)
+	require.NoError(t, err)
+	assert.Empty(t, people)
+	assert.Equal(t, "select id, name, created_at from people where (age > $1)", db.sql)
+
+	// The original statement is not modified.
+	sql, _ := pgsql.Build(ss)
+	assert.Equal(t, "select * from people where (age > $1)", sql)
+}
+
+func TestSelectAllGeneratesQualifiedSelectListWithJoins(t *testing.T) {
+	tests := []struct {
+		ss  *pgsql.SelectStatement
+		sql string
+	}{
+		{
+			ss:  pgsql.From("people").Join("teams", "teams.id = people.team_id"),
+			sql: "select people.id, people.name, people.created_at from people join teams on teams.id = people.team_id",
+		},
+		{
+			ss:  pgsql.From("people as p").Join("teams t", "t.id = p.team_id"),
+			sql: "select p.id, p.name, p.created_at from people as p join teams t on t.id = p.team_id",
+		},
+		{
+			ss:  pgsql.From(pgsql.Sub(pgsql.Select("*").From("people")).As("p")).Join("teams t", "t.id = p.team_id"),
+			sql: "select p.id, p.name, p.created_at from (select * from people) as p join teams t on t.id = p.team_id",
+		},
+	}
+
+	for i, tt := range tests {
+		db := &mockQuerier{rows: newFakeRows([]string{"id", "name", "created_at"})}
+		_, err := pgsql.SelectAll[scanPerson](context.Background(), db, tt.ss)
+		require.NoErrorf(t, err, "%d", i)
+		assert.Equalf(t, tt.sql, db.sql, "%d", i)
+	}
+
+	db := &mockQuerier{rows: newFakeRows([]string{"id"})}
+	_, err := pgsql.SelectAll[scanPerson](context.Background(), db, pgsql.From("generate_series(1, ?) n", 3).Join("people", "people.id = n"))
+	assert.EqualError(t, err, "pgsql: cannot generate select list with joins unless from is a table or aliased subquery")
+}
+
+func TestSelectOne(t *testing.T) {
+	db := &mockQuerier{rows: newFakeRows([]string{"name"}, []interface{}{"Alice"}, []interface{}{"Bob"})}
+	person, err := pgsql.SelectOne[scanPerson](context.Background(), db, pgsql.Select("name").From("people"))
+	require.NoError(t, err)
+	assert.Equal(t, scanPerson{Name: "Alice"}, person)
+	// Rows after the first are not read.
+	assert.Equal(t, 0, db.rows.(*fakeRows).row)
+	assert.True(t, db.rows.(*fakeRows).closed)
+
+	db = &mockQuerier{rows: newFakeRows([]string{"name"})}
+	_, err = pgsql.SelectOne[scanPerson](context.Background(), db, pgsql.Select("name").From("people"))
+	assert.ErrorIs(t, err, pgx.ErrNoRows)
+}
+
+func TestSelectIter(t *testing.T) {
+	db := &mockQuerier{rows: newFakeRows([]string{"id"}, []interface{}{int32(1)}, []interface{}{int32(2)}, []interface{}{int32(3)})}
+
+	var ids []int32
+	stop := errors.New("stop")
+	err := pgsql.SelectIter(context.Background(), db, pgsql.Select("id").From("people"), func(p scanPerson) error {
+		ids = append(ids, p.ID)
+		if len(ids) == 2 {
+			return stop
+		}
+		return nil
+	})
+	assert.ErrorIs(t, err, stop)
+	assert.Equal(t, []int32{1, 2}, ids)
+	assert.True(t, db.rows.(*fakeRows).closed)
+}
+
+func TestSelectAllErrors(t *testing.T) {
+	db := &mockQuerier{rows: newFakeRows([]string{"id", "unknown"})}
+	_, err := pgsql.SelectAll[scanPerson](context.Background(), db, pgsql.Select("id, unknown").From("people"))
+	assert.EqualError(t, err, `pgsql: no field in pgsql_test.scanPerson for column "unknown"`)
+
+	_, err = pgsql.SelectAll[int](context.Background(), db, pgsql.Select("id").From("people"))
+	assert.EqualError(t, err, "pgsql: cannot scan into int, it must be a struct")
+
+	_, err = pgsql.SelectAll[struct{ A int }](context.Background(), db, pgsql.From("people"))
+	assert.EqualError(t, err, "pgsql: cannot generate select list, struct { A int } has no fields with a db tag")
+
+	_, err = pgsql.SelectAll[scanPerson](context.Background(), db, pgsql.Select("id").Where("a = ?"))
+	assert.EqualError(t, err, `pgsql: "a = ?" has more placeholders than the 0 args provided`)
+}
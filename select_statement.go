@@ -2,44 +2,103 @@
 package pgsql
 
 import (
+	"errors"
+	"fmt"
 	"strconv"
 	"strings"
 )
 
 type SelectStatement struct {
+	withList withList
+
 	// select clause
 	distinctOnList []SQLWriter
 	selectList     []SQLWriter
 
 	from      SQLWriter
+	joinList  []*joinClause
 	whereList whereList
 
+	groupByList []SQLWriter
+	havingList  havingList
+
+	windowList windowList
+
 	orderByList []SQLWriter
 
 	limit  int64
 	offset int64
 
+	lockingList []*lockingClause
+
 	isDistinct     bool
 	replaceSelect  bool
 	replaceOrderBy bool
+
+	frozen bool
 }
 
 func Select(s string, args ...interface{}) *SelectStatement {
 	return (&SelectStatement{}).Select(s, args...)
 }
 
+func SelectExpr(expr SQLWriter) *SelectStatement {
+	return (&SelectStatement{}).SelectExpr(expr)
+}
+
 func ReplaceSelect(s string, args ...interface{}) *SelectStatement {
 	return (&SelectStatement{}).ReplaceSelect(s, args...)
 }
 
-func From(s string, args ...interface{}) *SelectStatement {
-	return (&SelectStatement{}).From(s, args...)
+func From(from interface{}, args ...interface{}) *SelectStatement {
+	return (&SelectStatement{}).From(from, args...)
+}
+
+func Join(table, on string, args ...interface{}) *SelectStatement {
+	return (&SelectStatement{}).Join(table, on, args...)
+}
+
+func LeftJoin(table, on string, args ...interface{}) *SelectStatement {
+	return (&SelectStatement{}).LeftJoin(table, on, args...)
+}
+
+func RightJoin(table, on string, args ...interface{}) *SelectStatement {
+	return (&SelectStatement{}).RightJoin(table, on, args...)
+}
+
+func FullJoin(table, on string, args ...interface{}) *SelectStatement {
+	return (&SelectStatement{}).FullJoin(table, on, args...)
+}
+
+func CrossJoin(table string, args ...interface{}) *SelectStatement {
+	return (&SelectStatement{}).CrossJoin(table, args...)
 }
 
 func Where(s string, args ...interface{}) *SelectStatement {
 	return (&SelectStatement{}).Where(s, args...)
 }
 
+func WhereExpr(expr SQLWriter) *SelectStatement {
+	return (&SelectStatement{}).WhereExpr(expr)
+}
+
+func GroupBy(s string, args ...interface{}) *SelectStatement {
+	return (&SelectStatement{}).GroupBy(s, args...)
+}
+
+func Having(s string, args ...interface{}) *SelectStatement {
+	return (&SelectStatement{}).Having(s, args...)
+}
+
+func HavingExpr(expr SQLWriter) *SelectStatement {
+	return (&SelectStatement{}).HavingExpr(expr)
+}
+
+// Window returns a select statement with the named window definition def. See SelectStatement.Window.
+func Window(name string, def *WindowDefinition) *SelectStatement {
+	return (&SelectStatement{}).Window(name, def)
+}
+
 func Order(s string, args ...interface{}) *SelectStatement {
 	return (&SelectStatement{}).Order(s, args...)
 }
@@ -52,12 +111,40 @@ func (ss *SelectStatement) SelectStatement() (*SelectStatement, error) {
 	return ss, nil
 }
 
+func (ss *SelectStatement) With(name string, query SQLWriter) *SelectStatement {
+	ss = ss.mutable()
+	ss.withList = append(ss.withList, &CommonTableExpression{Name: name, Query: query})
+	return ss
+}
+
+func (ss *SelectStatement) WithRecursive(name string, query SQLWriter) *SelectStatement {
+	ss = ss.mutable()
+	ss.withList = append(ss.withList, &CommonTableExpression{Name: name, Recursive: true, Query: query})
+	return ss
+}
+
+// WithCTE adds cte to the with clause. Use this instead of With when a column list or materialization is needed.
+func (ss *SelectStatement) WithCTE(cte *CommonTableExpression) *SelectStatement {
+	ss = ss.mutable()
+	ss.withList = append(ss.withList, cte)
+	return ss
+}
+
 func (ss *SelectStatement) Select(s string, args ...interface{}) *SelectStatement {
+	ss = ss.mutable()
 	ss.selectList = append(ss.selectList, &FormatString{s: s, args: args})
 	return ss
 }
 
+// SelectExpr adds expr to the select list. e.g. SelectExpr(WindowFunc("row_number()").Over(WindowDef().Order("id"))).
+func (ss *SelectStatement) SelectExpr(expr SQLWriter) *SelectStatement {
+	ss = ss.mutable()
+	ss.selectList = append(ss.selectList, checkExpr("select expression", expr))
+	return ss
+}
+
 func (ss *SelectStatement) Distinct(b bool) *SelectStatement {
+	ss = ss.mutable()
 	ss.isDistinct = b
 	if !b {
 		ss.distinctOnList = nil
@@ -67,51 +154,253 @@ func (ss *SelectStatement) Distinct(b bool) *SelectStatement {
 }
 
 func (ss *SelectStatement) DistinctOn(s string, args ...interface{}) *SelectStatement {
+	ss = ss.mutable()
 	ss.isDistinct = true
 	ss.distinctOnList = append(ss.distinctOnList, &FormatString{s: s, args: args})
 	return ss
 }
 
 func (ss *SelectStatement) ReplaceSelect(s string, args ...interface{}) *SelectStatement {
+	ss = ss.mutable()
 	ss.selectList = []SQLWriter{&FormatString{s: s, args: args}}
 	ss.replaceSelect = true
 	return ss
 }
 
-func (ss *SelectStatement) From(s string, args ...interface{}) *SelectStatement {
-	ss.from = &FormatString{s: s, args: args}
+// From sets the from item. from may be a format string with args or a SQLWriter such as Sub(stmt).As("t"). A statement
+// passed directly or a Sub without an alias causes an error when the statement is built.
+func (ss *SelectStatement) From(from interface{}, args ...interface{}) *SelectStatement {
+	ss = ss.mutable()
+	ss.from = newFromItem(from, args)
+	return ss
+}
+
+// Join adds an inner join of table on the condition on. args are consumed by the placeholders in table and then on.
+func (ss *SelectStatement) Join(table, on string, args ...interface{}) *SelectStatement {
+	ss = ss.mutable()
+	ss.joinList = append(ss.joinList, &joinClause{joinType: "join", table: table, on: on, args: args})
+	return ss
+}
+
+func (ss *SelectStatement) LeftJoin(table, on string, args ...interface{}) *SelectStatement {
+	ss = ss.mutable()
+	ss.joinList = append(ss.joinList, &joinClause{joinType: "left join", table: table, on: on, args: args})
+	return ss
+}
+
+func (ss *SelectStatement) RightJoin(table, on string, args ...interface{}) *SelectStatement {
+	ss = ss.mutable()
+	ss.joinList = append(ss.joinList, &joinClause{joinType: "right join", table: table, on: on, args: args})
+	return ss
+}
+
+func (ss *SelectStatement) FullJoin(table, on string, args ...interface{}) *SelectStatement {
+	ss = ss.mutable()
+	ss.joinList = append(ss.joinList, &joinClause{joinType: "full join", table: table, on: on, args: args})
 	return ss
 }
 
+func (ss *SelectStatement) CrossJoin(table string, args ...interface{}) *SelectStatement {
+	ss = ss.mutable()
+	ss.joinList = append(ss.joinList, &joinClause{joinType: "cross join", table: table, args: args})
+	return ss
+}
+
+// JoinUsing adds an inner join of table using columns.
+func (ss *SelectStatement) JoinUsing(table string, columns ...string) *SelectStatement {
+	ss = ss.mutable()
+	ss.joinList = append(ss.joinList, &joinClause{joinType: "join", table: table, using: columns})
+	return ss
+}
+
+func (ss *SelectStatement) LeftJoinUsing(table string, columns ...string) *SelectStatement {
+	ss = ss.mutable()
+	ss.joinList = append(ss.joinList, &joinClause{joinType: "left join", table: table, using: columns})
+	return ss
+}
+
+func (ss *SelectStatement) RightJoinUsing(table string, columns ...string) *SelectStatement {
+	ss = ss.mutable()
+	ss.joinList = append(ss.joinList, &joinClause{joinType: "right join", table: table, using: columns})
+	return ss
+}
+
+func (ss *SelectStatement) FullJoinUsing(table string, columns ...string) *SelectStatement {
+	ss = ss.mutable()
+	ss.joinList = append(ss.joinList, &joinClause{joinType: "full join", table: table, using: columns})
+	return ss
+}
+
+// JoinLateral adds an inner join lateral of table on the condition on. table is typically a parenthesized subquery
+// with an alias that refers to columns of preceding from items.
+func (ss *SelectStatement) JoinLateral(table, on string, args ...interface{}) *SelectStatement {
+	ss = ss.mutable()
+	ss.joinList = append(ss.joinList, &joinClause{joinType: "join", lateral: true, table: table, on: on, args: args})
+	return ss
+}
+
+func (ss *SelectStatement) LeftJoinLateral(table, on string, args ...interface{}) *SelectStatement {
+	ss = ss.mutable()
+	ss.joinList = append(ss.joinList, &joinClause{joinType: "left join", lateral: true, table: table, on: on, args: args})
+	return ss
+}
+
+func (ss *SelectStatement) CrossJoinLateral(table string, args ...interface{}) *SelectStatement {
+	ss = ss.mutable()
+	ss.joinList = append(ss.joinList, &joinClause{joinType: "cross join", lateral: true, table: table, args: args})
+	return ss
+}
+
+// Where adds a condition to the where clause. Multiple conditions are combined with and.
 func (ss *SelectStatement) Where(s string, args ...interface{}) *SelectStatement {
+	ss = ss.mutable()
 	ss.whereList = append(ss.whereList, &FormatString{s: s, args: args})
 	return ss
 }
 
+// WhereExpr adds an expression such as Eq(Col("id"), 42) to the where clause.
+func (ss *SelectStatement) WhereExpr(expr SQLWriter) *SelectStatement {
+	ss = ss.mutable()
+	ss.whereList = append(ss.whereList, checkExpr("condition", expr))
+	return ss
+}
+
+func (ss *SelectStatement) GroupBy(s string, args ...interface{}) *SelectStatement {
+	ss = ss.mutable()
+	ss.groupByList = append(ss.groupByList, &FormatString{s: s, args: args})
+	return ss
+}
+
+// Rollup adds a rollup grouping element. e.g. Rollup("brand, size") is rendered as "rollup (brand, size)".
+func (ss *SelectStatement) Rollup(s string, args ...interface{}) *SelectStatement {
+	ss = ss.mutable()
+	ss.groupByList = append(ss.groupByList, &FormatString{s: "rollup (" + s + ")", args: args})
+	return ss
+}
+
+// Cube adds a cube grouping element. e.g. Cube("brand, size") is rendered as "cube (brand, size)".
+func (ss *SelectStatement) Cube(s string, args ...interface{}) *SelectStatement {
+	ss = ss.mutable()
+	ss.groupByList = append(ss.groupByList, &FormatString{s: "cube (" + s + ")", args: args})
+	return ss
+}
+
+// GroupingSets adds a grouping sets element. e.g. GroupingSets("(brand), (size), ()") is rendered as
+// "grouping sets ((brand), (size), ())".
+func (ss *SelectStatement) GroupingSets(s string, args ...interface{}) *SelectStatement {
+	ss = ss.mutable()
+	ss.groupByList = append(ss.groupByList, &FormatString{s: "grouping sets (" + s + ")", args: args})
+	return ss
+}
+
+func (ss *SelectStatement) Having(s string, args ...interface{}) *SelectStatement {
+	ss = ss.mutable()
+	ss.havingList = append(ss.havingList, &FormatString{s: s, args: args})
+	return ss
+}
+
+// HavingExpr adds an expression to the having clause. See WhereExpr.
+func (ss *SelectStatement) HavingExpr(expr SQLWriter) *SelectStatement {
+	ss = ss.mutable()
+	ss.havingList = append(ss.havingList, checkExpr("condition", expr))
+	return ss
+}
+
+// Window adds a named window definition to the window clause. Window functions refer to it with OverWindow(name).
+func (ss *SelectStatement) Window(name string, def *WindowDefinition) *SelectStatement {
+	ss = ss.mutable()
+	ss.windowList = append(ss.windowList, &namedWindow{name: name, def: def})
+	return ss
+}
+
 func (ss *SelectStatement) Order(s string, args ...interface{}) *SelectStatement {
+	ss = ss.mutable()
 	ss.orderByList = append(ss.orderByList, &FormatString{s: s, args: args})
 	return ss
 }
 
 func (ss *SelectStatement) ReplaceOrder(s string, args ...interface{}) *SelectStatement {
+	ss = ss.mutable()
 	ss.orderByList = []SQLWriter{&FormatString{s: s, args: args}}
 	ss.replaceOrderBy = true
 	return ss
 }
 
 func (ss *SelectStatement) Limit(n int64) *SelectStatement {
+	ss = ss.mutable()
 	ss.limit = n
 	return ss
 }
 
 func (ss *SelectStatement) Offset(n int64) *SelectStatement {
+	ss = ss.mutable()
 	ss.offset = n
 	return ss
 }
 
-// Apply merges other's from, where, order, limit and offset if they are set.
+func (ss *SelectStatement) ForUpdate() *SelectStatement {
+	ss = ss.mutable()
+	ss.lockingList = append(ss.lockingList, &lockingClause{strength: "update"})
+	return ss
+}
+
+func (ss *SelectStatement) ForNoKeyUpdate() *SelectStatement {
+	ss = ss.mutable()
+	ss.lockingList = append(ss.lockingList, &lockingClause{strength: "no key update"})
+	return ss
+}
+
+func (ss *SelectStatement) ForShare() *SelectStatement {
+	ss = ss.mutable()
+	ss.lockingList = append(ss.lockingList, &lockingClause{strength: "share"})
+	return ss
+}
+
+func (ss *SelectStatement) ForKeyShare() *SelectStatement {
+	ss = ss.mutable()
+	ss.lockingList = append(ss.lockingList, &lockingClause{strength: "key share"})
+	return ss
+}
+
+// Of restricts the most recently added locking clause to tables.
+func (ss *SelectStatement) Of(tables ...string) *SelectStatement {
+	ss = ss.mutable()
+	lc := ss.lastLockingClause("Of")
+	lc.tables = append(lc.tables, tables...)
+	return ss
+}
+
+// NoWait sets the most recently added locking clause to fail immediately if a row cannot be locked.
+func (ss *SelectStatement) NoWait() *SelectStatement {
+	ss = ss.mutable()
+	ss.lastLockingClause("NoWait").waitPolicy = "nowait"
+	return ss
+}
+
+// SkipLocked sets the most recently added locking clause to skip rows that cannot be locked immediately.
+func (ss *SelectStatement) SkipLocked() *SelectStatement {
+	ss = ss.mutable()
+	ss.lastLockingClause("SkipLocked").waitPolicy = "skip locked"
+	return ss
+}
+
+// lastLockingClause returns the most recently added locking clause. If there is none a clause that reports an error
+// is added.
+func (ss *SelectStatement) lastLockingClause(method string) *lockingClause {
+	if len(ss.lockingList) == 0 {
+		err := fmt.Errorf("pgsql: %s requires a preceding locking clause such as ForUpdate", method)
+		ss.lockingList = append(ss.lockingList, &lockingClause{err: err})
+	}
+	return ss.lockingList[len(ss.lockingList)-1]
+}
+
+// Apply merges other's with, select, from, joins, where, group by, having, window, order, limit, offset and locking
+// clauses if they are set.
 func (ss *SelectStatement) Apply(others ...*SelectStatement) *SelectStatement {
+	ss = ss.mutable()
 	for _, other := range others {
+		ss.withList = append(ss.withList, other.withList...)
+
 		if other.replaceSelect {
 			ss.selectList = []SQLWriter{}
 		}
@@ -121,7 +410,12 @@ func (ss *SelectStatement) Apply(others ...*SelectStatement) *SelectStatement {
 			ss.from = other.from
 		}
 
+		ss.joinList = append(ss.joinList, other.joinList...)
+
 		ss.whereList = append(ss.whereList, other.whereList...)
+		ss.groupByList = append(ss.groupByList, other.groupByList...)
+		ss.havingList = append(ss.havingList, other.havingList...)
+		ss.windowList = append(ss.windowList, other.windowList...)
 
 		if other.replaceOrderBy {
 			ss.orderByList = []SQLWriter{}
@@ -135,12 +429,141 @@ func (ss *SelectStatement) Apply(others ...*SelectStatement) *SelectStatement {
 		if other.offset != 0 {
 			ss.offset = other.offset
 		}
+
+		for _, lc := range other.lockingList {
+			// Copy the clause so Of, NoWait and SkipLocked on ss cannot modify other.
+			ss.lockingList = append(ss.lockingList, lc.clone())
+		}
 	}
 
 	return ss
 }
 
+// Count returns a new statement that counts the rows ss returns. The order by, limit, offset and locking clauses are
+// dropped. If the select list only contains columns such as "*", "people.*" or "id, name as n" it is replaced with
+// count(*). Otherwise, or if ss is distinct or has a group by or having clause, ss is counted as a subquery. This keeps
+// the count correct for aggregates, set-returning functions and window functions in the select list. ss is not
+// modified.
+func (ss *SelectStatement) Count() *SelectStatement {
+	cs := ss.Clone()
+	cs.orderByList = nil
+	cs.limit = 0
+	cs.offset = 0
+	cs.lockingList = nil
+	cs.replaceSelect = false
+	cs.replaceOrderBy = false
+
+	if cs.isDistinct || len(cs.groupByList) > 0 || len(cs.havingList) > 0 || !isColumnList(cs.selectList) {
+		outer := &SelectStatement{withList: cs.withList}
+		cs.withList = nil
+		return outer.Select("count(*)").From(Sub(cs).As("counted"))
+	}
+
+	cs.selectList = []SQLWriter{rawSQL("count(*)")}
+	cs.windowList = nil
+	return cs
+}
+
+// isColumnList reports whether every item in selectList is known to be a plain column reference, optionally with an
+// alias. Anything else, including a function call, may change the number of rows when it is replaced with count(*).
+func isColumnList(selectList []SQLWriter) bool {
+	for _, item := range selectList {
+		switch item := item.(type) {
+		case Column:
+		case *FormatString:
+			if len(item.args) > 0 {
+				return false
+			}
+			for _, s := range strings.Split(item.s, ",") {
+				if !isColumnListItem(s) {
+					return false
+				}
+			}
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// isColumnListItem reports whether s is "*", a possibly qualified column name or "table.*", followed by an optional
+// "as alias".
+func isColumnListItem(s string) bool {
+	fields := strings.Fields(s)
+	switch {
+	case len(fields) == 1:
+	case len(fields) == 3 && strings.EqualFold(fields[1], "as") && isPlainName(fields[2]):
+	default:
+		return false
+	}
+
+	name := fields[0]
+	if name == "*" {
+		return true
+	}
+	return isPlainName(strings.TrimSuffix(name, ".*"))
+}
+
+// isPlainName reports whether name is a possibly qualified identifier that is not a key word such as distinct.
+func isPlainName(name string) bool {
+	parts, ok := splitName(name)
+	if !ok {
+		return false
+	}
+	for _, p := range parts {
+		if _, reserved := reservedWords[strings.ToLower(p)]; reserved {
+			return false
+		}
+	}
+	return true
+}
+
+// Clone returns a copy of ss that can be modified without affecting ss. Common table expressions and a from item that
+// is a statement or subquery are copied too. Other SQLWriters such as expressions are shared. The copy is not frozen.
+func (ss *SelectStatement) Clone() *SelectStatement {
+	c := *ss
+	c.withList = ss.withList.clone()
+	c.from = cloneStatement(ss.from)
+	c.distinctOnList = cloneSlice(ss.distinctOnList)
+	c.selectList = cloneSlice(ss.selectList)
+	c.joinList = cloneSlice(ss.joinList)
+	c.whereList = cloneSlice(ss.whereList)
+	c.groupByList = cloneSlice(ss.groupByList)
+	c.havingList = cloneSlice(ss.havingList)
+	c.windowList = cloneSlice(ss.windowList)
+	c.orderByList = cloneSlice(ss.orderByList)
+	c.lockingList = nil
+	for _, lc := range ss.lockingList {
+		c.lockingList = append(c.lockingList, lc.clone())
+	}
+	c.frozen = false
+	return &c
+}
+
+// Freeze makes ss copy-on-write. Builder methods called on a frozen statement modify and return a clone, leaving ss
+// unchanged. This allows a base query to be stored in a package level variable and extended concurrently. The result
+// of each builder method must be used. e.g.
+//
+//	var people = pgsql.Select("*").From("people").Freeze()
+//	adults := people.Where("age >= ?", 18)
+//
+// Only statement builder methods are copy-on-write. Helpers such as Sub(...).As, InExpr.AsAny and the WindowDefinition
+// builders modify their receiver. Finish building them before they are added to a frozen statement.
+func (ss *SelectStatement) Freeze() *SelectStatement {
+	ss.frozen = true
+	return ss
+}
+
+// mutable returns ss or a clone of ss if ss is frozen.
+func (ss *SelectStatement) mutable() *SelectStatement {
+	if ss.frozen {
+		return ss.Clone()
+	}
+	return ss
+}
+
 func (ss *SelectStatement) WriteSQL(sb *strings.Builder, args *Args) {
+	ss.withList.WriteSQL(sb, args)
 	sb.WriteString("select")
 	if ss.isDistinct {
 		sb.WriteString(" distinct")
@@ -174,8 +597,25 @@ func (ss *SelectStatement) WriteSQL(sb *strings.Builder, args *Args) {
 		ss.from.WriteSQL(sb, args)
 	}
 
+	for _, j := range ss.joinList {
+		j.WriteSQL(sb, args)
+	}
+
 	ss.whereList.WriteSQL(sb, args)
 
+	if len(ss.groupByList) > 0 {
+		sb.WriteString(" group by ")
+		for i, e := range ss.groupByList {
+			if i > 0 {
+				sb.WriteString(", ")
+			}
+			e.WriteSQL(sb, args)
+		}
+	}
+
+	ss.havingList.WriteSQL(sb, args)
+	ss.windowList.WriteSQL(sb, args)
+
 	if len(ss.orderByList) > 0 {
 		sb.WriteString(" order by ")
 		for i, e := range ss.orderByList {
@@ -186,6 +626,10 @@ func (ss *SelectStatement) WriteSQL(sb *strings.Builder, args *Args) {
 		}
 	}
 
+	if ss.limit < 0 || ss.offset < 0 {
+		args.SetError(errors.New("pgsql: limit and offset must not be negative"))
+	}
+
 	if ss.limit != 0 {
 		sb.WriteString(" limit ")
 		sb.WriteString(strconv.FormatInt(ss.limit, 10))
@@ -194,4 +638,102 @@ func (ss *SelectStatement) WriteSQL(sb *strings.Builder, args *Args) {
 		sb.WriteString(" offset ")
 		sb.WriteString(strconv.FormatInt(ss.offset, 10))
 	}
+
+	for _, lc := range ss.lockingList {
+		lc.WriteSQL(sb, args)
+	}
+}
+
+type joinClause struct {
+	joinType string
+	lateral  bool
+	table    string
+	on       string
+	using    []string
+	args     []interface{}
+}
+
+func (jc *joinClause) WriteSQL(sb *strings.Builder, args *Args) {
+	if jc.table == "" {
+		args.SetError(fmt.Errorf("pgsql: %s table is empty", jc.joinType))
+	}
+
+	sb.WriteByte(' ')
+	sb.WriteString(jc.joinType)
+	sb.WriteByte(' ')
+	if jc.lateral {
+		sb.WriteString("lateral ")
+	}
+
+	s := jc.table
+	if jc.on != "" {
+		s += " on " + jc.on
+	}
+	sb.WriteString(args.Format(s, jc.args...))
+
+	if len(jc.using) > 0 {
+		sb.WriteString(" using (")
+		nameList(jc.using).WriteSQL(sb, args)
+		sb.WriteByte(')')
+	}
+}
+
+// tableAndCondition returns the table and on condition of the join as separate format strings. args are divided
+// according to the placeholders in table. Named args are shared by both.
+func (jc *joinClause) tableAndCondition() (table, on *FormatString) {
+	tableArgs, onArgs := jc.args, jc.args
+	if _, named := asNamedArgs(jc.args); !named {
+		n := countPlaceholders(jc.table)
+		if n > len(jc.args) {
+			n = len(jc.args)
+		}
+		tableArgs, onArgs = jc.args[:n:n], jc.args[n:]
+	}
+
+	table = &FormatString{s: jc.table, args: tableArgs}
+	if jc.lateral {
+		table.s = "lateral " + table.s
+	}
+
+	if jc.on != "" {
+		on = &FormatString{s: jc.on, args: onArgs}
+	} else if len(onArgs) > 0 {
+		// Let the table report the mismatched argument count.
+		table.args = jc.args
+	}
+
+	return table, on
+}
+
+type lockingClause struct {
+	strength   string
+	tables     []string
+	waitPolicy string
+	err        error
+}
+
+func (lc *lockingClause) clone() *lockingClause {
+	c := *lc
+	c.tables = cloneSlice(lc.tables)
+	return &c
+}
+
+func (lc *lockingClause) WriteSQL(sb *strings.Builder, args *Args) {
+	if lc.err != nil {
+		args.SetError(lc.err)
+		return
+	}
+
+	sb.WriteString(" for ")
+	sb.WriteString(lc.strength)
+
+	if len(lc.tables) > 0 {
+		sb.WriteString(" of ")
+		nameList(lc.tables).WriteSQL(sb, args)
+	}
+
+	if lc.waitPolicy != "" {
+		sb.WriteByte(' ')
+		sb.WriteString(lc.waitPolicy)
+	}
 }
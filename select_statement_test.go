@@ -1,6 +1,7 @@
 package pgsql_test
 
 import (
+	"sync"
 	"testing"
 
 	"github.com/jackc/pgsql"
@@ -160,3 +161,303 @@ func TestSelectStatementApplyReplaceOrder(t *testing.T) {
 	assert.Equal(t, "select a, b, c from t order by a desc, d", sql)
 	assert.Empty(t, args)
 }
+
+func TestSelectStatementJoin(t *testing.T) {
+	a := pgsql.Select("p.name, t.name").From("people p").Join("teams t", "t.id = p.team_id and t.active = ?", true)
+	sql, args := pgsql.Build(a)
+	assert.Equal(t, "select p.name, t.name from people p join teams t on t.id = p.team_id and t.active = $1", sql)
+	assert.Equal(t, []interface{}{true}, args)
+
+	a.LeftJoin("offices o", "o.id = p.office_id").RightJoin("desks d", "d.person_id = p.id").FullJoin("phones ph", "ph.desk_id = d.id")
+	sql, args = pgsql.Build(a)
+	assert.Equal(t, "select p.name, t.name from people p join teams t on t.id = p.team_id and t.active = $1 left join offices o on o.id = p.office_id right join desks d on d.person_id = p.id full join phones ph on ph.desk_id = d.id", sql)
+	assert.Equal(t, []interface{}{true}, args)
+}
+
+func TestSelectStatementJoinUsing(t *testing.T) {
+	a := pgsql.From("people").JoinUsing("teams", "team_id").LeftJoinUsing("offices", "office_id", "region_id")
+	sql, args := pgsql.Build(a)
+	assert.Equal(t, "select * from people join teams using (team_id) left join offices using (office_id, region_id)", sql)
+	assert.Empty(t, args)
+}
+
+func TestSelectStatementCrossJoin(t *testing.T) {
+	a := pgsql.From("people").CrossJoin("generate_series(1, ?) n", 3)
+	sql, args := pgsql.Build(a)
+	assert.Equal(t, "select * from people cross join generate_series(1, $1) n", sql)
+	assert.Equal(t, []interface{}{3}, args)
+}
+
+func TestSelectStatementJoinLateral(t *testing.T) {
+	a := pgsql.From("people p").
+		Where("p.age > ?", 30).
+		LeftJoinLateral("(select * from posts where author_id = p.id order by created_at desc limit ?) lp", "true", 3).
+		CrossJoinLateral("unnest(p.tags) tag")
+	sql, args := pgsql.Build(a)
+	assert.Equal(t, "select * from people p left join lateral (select * from posts where author_id = p.id order by created_at desc limit $1) lp on true cross join lateral unnest(p.tags) tag where (p.age > $2)", sql)
+	assert.Equal(t, []interface{}{3, 30}, args)
+}
+
+func TestSelectStatementApplyJoin(t *testing.T) {
+	a := pgsql.Select("p.name").From("people p")
+	b := pgsql.Join("teams t", "t.id = p.team_id").Where("t.name = ?", "red")
+	c := pgsql.LeftJoin("offices o", "o.id = p.office_id and o.city = ?", "Dallas")
+	a.Apply(b, c)
+
+	sql, args := pgsql.Build(a)
+	assert.Equal(t, "select p.name from people p join teams t on t.id = p.team_id left join offices o on o.id = p.office_id and o.city = $1 where (t.name = $2)", sql)
+	assert.Equal(t, []interface{}{"Dallas", "red"}, args)
+}
+
+func TestSelectStatementGroupBy(t *testing.T) {
+	a := pgsql.Select("brand, count(*)").From("products").GroupBy("brand")
+	sql, args := pgsql.Build(a)
+	assert.Equal(t, "select brand, count(*) from products group by brand", sql)
+	assert.Empty(t, args)
+
+	a.GroupBy("date_trunc(?, created_at)", "month")
+	sql, args = pgsql.Build(a)
+	assert.Equal(t, "select brand, count(*) from products group by brand, date_trunc($1, created_at)", sql)
+	assert.Equal(t, []interface{}{"month"}, args)
+}
+
+func TestSelectStatementHaving(t *testing.T) {
+	a := pgsql.Select("brand, count(*)").From("products").Where("price > ?", 10).GroupBy("brand").Having("count(*) > ?", 5)
+	sql, args := pgsql.Build(a)
+	assert.Equal(t, "select brand, count(*) from products where (price > $1) group by brand having (count(*) > $2)", sql)
+	assert.Equal(t, []interface{}{10, 5}, args)
+
+	a.Having("sum(price) < ?", 1000).Order("brand")
+	sql, args = pgsql.Build(a)
+	assert.Equal(t, "select brand, count(*) from products where (price > $1) group by brand having (count(*) > $2) and (sum(price) < $3) order by brand", sql)
+	assert.Equal(t, []interface{}{10, 5, 1000}, args)
+}
+
+func TestSelectStatementGroupingSets(t *testing.T) {
+	a := pgsql.Select("brand, size, sum(sales)").From("items_sold").GroupingSets("(brand), (size), ()")
+	sql, args := pgsql.Build(a)
+	assert.Equal(t, "select brand, size, sum(sales) from items_sold group by grouping sets ((brand), (size), ())", sql)
+	assert.Empty(t, args)
+
+	a = pgsql.Select("brand, size, sum(sales)").From("items_sold").GroupBy("region").Rollup("brand, size")
+	sql, args = pgsql.Build(a)
+	assert.Equal(t, "select brand, size, sum(sales) from items_sold group by region, rollup (brand, size)", sql)
+	assert.Empty(t, args)
+
+	a = pgsql.Select("brand, size, sum(sales)").From("items_sold").Cube("brand, size")
+	sql, args = pgsql.Build(a)
+	assert.Equal(t, "select brand, size, sum(sales) from items_sold group by cube (brand, size)", sql)
+	assert.Empty(t, args)
+}
+
+func TestSelectStatementApplyGroupByAndHaving(t *testing.T) {
+	a := pgsql.Select("brand, count(*)").From("products")
+	b := pgsql.GroupBy("brand").Having("count(*) > ?", 5)
+	c := pgsql.Having("max(price) < ?", 100)
+	a.Apply(b, c)
+
+	sql, args := pgsql.Build(a)
+	assert.Equal(t, "select brand, count(*) from products group by brand having (count(*) > $1) and (max(price) < $2)", sql)
+	assert.Equal(t, []interface{}{5, 100}, args)
+}
+
+func TestSelectStatementForUpdate(t *testing.T) {
+	a := pgsql.Select("*").From("jobs").Where("queue = ?", "default").Order("id").Limit(1).ForUpdate().SkipLocked()
+	sql, args := pgsql.Build(a)
+	assert.Equal(t, "select * from jobs where (queue = $1) order by id limit 1 for update skip locked", sql)
+	assert.Equal(t, []interface{}{"default"}, args)
+}
+
+func TestSelectStatementLockingClauses(t *testing.T) {
+	a := pgsql.Select("*").From("jobs j").Join("queues q", "q.id = j.queue_id").Offset(5)
+	a.ForNoKeyUpdate().Of("j").NoWait()
+	a.ForKeyShare().Of("q")
+	sql, args := pgsql.Build(a)
+	assert.Equal(t, "select * from jobs j join queues q on q.id = j.queue_id offset 5 for no key update of j nowait for key share of q", sql)
+	assert.Empty(t, args)
+
+	a = pgsql.Select("*").From("a, b").ForShare().Of("a", "b")
+	sql, args = pgsql.Build(a)
+	assert.Equal(t, "select * from a, b for share of a, b", sql)
+	assert.Empty(t, args)
+}
+
+func TestSelectStatementApplyLockingClause(t *testing.T) {
+	scope := pgsql.Where("locked_at is null").ForUpdate()
+
+	a := pgsql.Select("*").From("jobs").Apply(scope).SkipLocked()
+	sql, args := pgsql.Build(a)
+	assert.Equal(t, "select * from jobs where (locked_at is null) for update skip locked", sql)
+	assert.Empty(t, args)
+
+	b := pgsql.Select("*").From("jobs").Apply(scope)
+	sql, args = pgsql.Build(b)
+	assert.Equal(t, "select * from jobs where (locked_at is null) for update", sql)
+	assert.Empty(t, args)
+}
+
+func TestSelectStatementCount(t *testing.T) {
+	scope := pgsql.Where("active = ?", true).Join("teams", "teams.id = people.team_id")
+	list := pgsql.Select("people.*").From("people").Apply(scope).Where("age > ?", 21).Order("name").Limit(10).Offset(20).ForUpdate()
+
+	sql, args := pgsql.Build(list.Count())
+	assert.Equal(t, "select count(*) from people join teams on teams.id = people.team_id where (active = $1) and (age > $2)", sql)
+	assert.Equal(t, []interface{}{true, 21}, args)
+
+	sql, _ = pgsql.Build(list)
+	assert.Equal(t, "select people.* from people join teams on teams.id = people.team_id where (active = $1) and (age > $2) order by name limit 10 offset 20 for update", sql)
+}
+
+func TestSelectStatementCountSubquery(t *testing.T) {
+	tests := []struct {
+		ss  *pgsql.SelectStatement
+		sql string
+	}{
+		{
+			ss:  pgsql.Select("city").Distinct(true).From("people").Order("city"),
+			sql: "select count(*) from (select distinct city from people) as counted",
+		},
+		{
+			ss:  pgsql.Select("*").DistinctOn("team_id").From("people").Order("team_id, age desc").Limit(5),
+			sql: "select count(*) from (select distinct on (team_id) * from people) as counted",
+		},
+		{
+			ss:  pgsql.Select("team_id, count(*)").From("people").GroupBy("team_id").Having("count(*) > ?", 3),
+			sql: "select count(*) from (select team_id, count(*) from people group by team_id having (count(*) > $1)) as counted",
+		},
+		{
+			ss:  pgsql.Select("n").From("t").GroupBy("n").With("t", pgsql.Select("1 as n")),
+			sql: "with t as (select 1 as n) select count(*) from (select n from t group by n) as counted",
+		},
+		{
+			ss:  pgsql.Select("max(age)").From("people").Where("team_id = ?", 1),
+			sql: "select count(*) from (select max(age) from people where (team_id = $1)) as counted",
+		},
+		{
+			ss:  pgsql.Select("id, unnest(tags) as tag").From("people"),
+			sql: "select count(*) from (select id, unnest(tags) as tag from people) as counted",
+		},
+		{
+			ss:  pgsql.Select("id, row_number() over (order by id)").From("people"),
+			sql: "select count(*) from (select id, row_number() over (order by id) from people) as counted",
+		},
+		{
+			ss:  pgsql.Select("distinct team_id").From("people"),
+			sql: "select count(*) from (select distinct team_id from people) as counted",
+		},
+		{
+			ss:  pgsql.Select("?::int as n", 1).From("people"),
+			sql: "select count(*) from (select $1::int as n from people) as counted",
+		},
+	}
+
+	for i, tt := range tests {
+		sql, _ := pgsql.Build(tt.ss.Count())
+		assert.Equalf(t, tt.sql, sql, "%d", i)
+	}
+}
+
+func TestSelectStatementCountColumnList(t *testing.T) {
+	tests := []*pgsql.SelectStatement{
+		pgsql.From("people"),
+		pgsql.Select("*").From("people"),
+		pgsql.Select("p.id, p.name as n").Select(`"Order"`).From("people p"),
+		pgsql.SelectExpr(pgsql.Col("people.id")).From("people"),
+	}
+
+	for i, ss := range tests {
+		sql, _ := pgsql.Build(ss.Count())
+		assert.Regexpf(t, `^select count\(\*\) from people`, sql, "%d", i)
+	}
+}
+
+func TestSelectStatementCountDoesNotShareState(t *testing.T) {
+	list := pgsql.Select("*").From("people").Where("a = ?", 1)
+	count := list.Count().Where("b = ?", 2)
+	list.Where("c = ?", 3)
+
+	sql, _ := pgsql.Build(count)
+	assert.Equal(t, "select count(*) from people where (a = $1) and (b = $2)", sql)
+	sql, _ = pgsql.Build(list)
+	assert.Equal(t, "select * from people where (a = $1) and (c = $2)", sql)
+}
+
+func TestSelectStatementClone(t *testing.T) {
+	base := pgsql.Select("id").From("people").Where("a = ?", 1).Order("id").ForUpdate()
+	// Spare capacity would let appends to a shallow copy overwrite each other.
+	base.Where("b = ?", 2)
+
+	c1 := base.Clone().Where("c = ?", 3).Of("people")
+	c2 := base.Clone().Where("d = ?", 4).NoWait()
+
+	sql, args := pgsql.Build(c1)
+	assert.Equal(t, "select id from people where (a = $1) and (b = $2) and (c = $3) order by id for update of people", sql)
+	assert.Equal(t, []interface{}{1, 2, 3}, args)
+
+	sql, args = pgsql.Build(c2)
+	assert.Equal(t, "select id from people where (a = $1) and (b = $2) and (d = $3) order by id for update nowait", sql)
+	assert.Equal(t, []interface{}{1, 2, 4}, args)
+
+	sql, _ = pgsql.Build(base)
+	assert.Equal(t, "select id from people where (a = $1) and (b = $2) order by id for update", sql)
+}
+
+func TestSelectStatementCloneCopiesNestedStatements(t *testing.T) {
+	active := pgsql.Select("id").From("teams")
+	people := pgsql.Select("*").From("people")
+	base := pgsql.Select("p.id").With("active", active).From(pgsql.Sub(people).As("p"))
+	c := base.Clone()
+	active.Where("active")
+	people.Where("age > ?", 21)
+
+	sql, args := pgsql.Build(c)
+	assert.Equal(t, "with active as (select id from teams) select p.id from (select * from people) as p", sql)
+	assert.Empty(t, args)
+}
+
+func TestSelectStatementFreezeConcurrent(t *testing.T) {
+	base := pgsql.Select("*").From("people").Where("active = ?", true).Freeze()
+	scope := pgsql.Where("team_id = ?", 7).Freeze()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				ss := base.Where("age > ?", i).Apply(scope).Order("name")
+				sql, args := pgsql.Build(ss)
+				assert.Equal(t, "select * from people where (active = $1) and (age > $2) and (team_id = $3) order by name", sql)
+				assert.Equal(t, []interface{}{true, i, 7}, args)
+
+				sql, _ = pgsql.Build(base)
+				assert.Equal(t, "select * from people where (active = $1)", sql)
+			}
+		}(i)
+	}
+	wg.Wait()
+}
+
+func TestSelectStatementFreeze(t *testing.T) {
+	base := pgsql.Select("*").From("people").Where("active = ?", true).Freeze()
+
+	adults := base.Where("age >= ?", 18).Order("name").Limit(10)
+	children := base.Where("age < ?", 18)
+	scoped := base.Apply(pgsql.Where("team_id = ?", 7))
+
+	sql, args := pgsql.Build(adults)
+	assert.Equal(t, "select * from people where (active = $1) and (age >= $2) order by name limit 10", sql)
+	assert.Equal(t, []interface{}{true, 18}, args)
+
+	sql, _ = pgsql.Build(children)
+	assert.Equal(t, "select * from people where (active = $1) and (age < $2)", sql)
+
+	sql, _ = pgsql.Build(scoped)
+	assert.Equal(t, "select * from people where (active = $1) and (team_id = $2)", sql)
+
+	sql, _ = pgsql.Build(base)
+	assert.Equal(t, "select * from people where (active = $1)", sql)
+
+	// The result of a builder method on a frozen statement is not frozen.
+	assert.Same(t, adults, adults.Offset(5))
+}
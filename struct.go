@@ -0,0 +1,223 @@
+package pgsql
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// structField is a struct field that maps to a column.
+type structField struct {
+	column string
+	tagged bool
+	index  []int
+
+	omitEmpty     bool
+	readOnly      bool
+	insertDefault bool
+	primaryKey    bool
+}
+
+// structInfo is the column mapping of a struct type. It is computed once per type.
+type structInfo struct {
+	fields []*structField
+	tagged map[string]*structField
+}
+
+var structInfoCache sync.Map
+
+func getStructInfo(t reflect.Type) *structInfo {
+	if si, ok := structInfoCache.Load(t); ok {
+		return si.(*structInfo)
+	}
+
+	si := &structInfo{tagged: make(map[string]*structField)}
+	si.addFields(t, nil)
+
+	actual, _ := structInfoCache.LoadOrStore(t, si)
+	return actual.(*structInfo)
+}
+
+// addFields adds the fields of t. The fields of embedded structs without a db tag are added as if they were fields of
+// t.
+func (si *structInfo) addFields(t reflect.Type, parentIndex []int) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+
+		tag := f.Tag.Get("db")
+		if tag == "-" {
+			continue
+		}
+		tagParts := strings.Split(tag, ",")
+		name := tagParts[0]
+
+		index := make([]int, len(parentIndex)+1)
+		copy(index, parentIndex)
+		index[len(parentIndex)] = i
+
+		if f.Anonymous && name == "" && f.Type.Kind() == reflect.Struct {
+			si.addFields(f.Type, index)
+			continue
+		}
+
+		if f.PkgPath != "" {
+			continue
+		}
+
+		sf := &structField{column: name, tagged: name != "", index: index}
+		if !sf.tagged {
+			sf.column = f.Name
+		}
+		for _, option := range tagParts[1:] {
+			switch option {
+			case "omitempty":
+				sf.omitEmpty = true
+			case "readonly":
+				sf.readOnly = true
+			case "insertdefault":
+				sf.insertDefault = true
+			case "pk":
+				sf.primaryKey = true
+			}
+		}
+
+		si.fields = append(si.fields, sf)
+		if sf.tagged {
+			if _, present := si.tagged[sf.column]; !present {
+				si.tagged[sf.column] = sf
+			}
+		}
+	}
+}
+
+// lookup returns the field for column. A field with a db tag must match exactly. A field without a db tag matches its
+// field name case-insensitively.
+func (si *structInfo) lookup(column string) (*structField, bool) {
+	if sf, ok := si.tagged[column]; ok {
+		return sf, true
+	}
+
+	for _, sf := range si.fields {
+		if !sf.tagged && strings.EqualFold(sf.column, column) {
+			return sf, true
+		}
+	}
+
+	return nil, false
+}
+
+// taggedColumns returns the names of all columns with a db tag in field order.
+func (si *structInfo) taggedColumns() []string {
+	columns := make([]string, 0, len(si.fields))
+	for _, sf := range si.fields {
+		if sf.tagged {
+			columns = append(columns, sf.column)
+		}
+	}
+	return columns
+}
+
+// StructData adapts a struct to Insertable and Updateable. It is created by Struct.
+type StructData struct {
+	v   reflect.Value
+	si  *structInfo
+	err error
+}
+
+// Struct returns v as Insertable and Updateable data. v must be a struct or a pointer to a struct. Only fields with a
+// db tag are used. The following options may follow the column name in the tag:
+//
+//	omitempty     - the field is omitted from inserts and updates when it is the zero value
+//	readonly      - the field is omitted from updates
+//	insertdefault - default is inserted instead of the field when it is the zero value
+//	pk            - the field is part of the primary key. It is omitted from updates. See WherePrimaryKey.
+//
+// e.g. `db:"id,pk,insertdefault"`
+func Struct(v interface{}) *StructData {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Ptr && !rv.IsNil() {
+		rv = rv.Elem()
+	}
+
+	if rv.Kind() != reflect.Struct {
+		return &StructData{err: fmt.Errorf("pgsql: Struct requires a struct or pointer to a struct, got %T", v)}
+	}
+
+	return &StructData{v: rv, si: getStructInfo(rv.Type())}
+}
+
+func (sd *StructData) InsertData() ([]string, *ValuesStatement) {
+	if sd.err != nil {
+		return nil, Values().Row(&errorWriter{err: sd.err})
+	}
+
+	columns := make([]string, 0, len(sd.si.fields))
+	values := make([]interface{}, 0, len(sd.si.fields))
+	for _, sf := range sd.si.fields {
+		if !sf.tagged {
+			continue
+		}
+
+		fv := sd.v.FieldByIndex(sf.index)
+		if fv.IsZero() {
+			if sf.insertDefault {
+				columns = append(columns, sf.column)
+				values = append(values, rawSQL("default"))
+				continue
+			}
+			if sf.omitEmpty {
+				continue
+			}
+		}
+
+		columns = append(columns, sf.column)
+		values = append(values, fv.Interface())
+	}
+
+	return columns, Values().Row(values...)
+}
+
+func (sd *StructData) UpdateData() []*Assignment {
+	if sd.err != nil {
+		return []*Assignment{{Left: &errorWriter{err: sd.err}, Right: &errorWriter{err: sd.err}}}
+	}
+
+	assignments := make([]*Assignment, 0, len(sd.si.fields))
+	for _, sf := range sd.si.fields {
+		if !sf.tagged || sf.readOnly || sf.primaryKey {
+			continue
+		}
+
+		fv := sd.v.FieldByIndex(sf.index)
+		if sf.omitEmpty && fv.IsZero() {
+			continue
+		}
+
+		assignments = append(assignments, &Assignment{Left: Column(sf.column), Right: &Param{Value: fv.Interface()}})
+	}
+
+	return assignments
+}
+
+// WherePrimaryKey returns a condition that matches the row identified by the fields tagged with the pk option. It is
+// intended for use with UpdateStatement.WhereExpr and DeleteStatement.WhereExpr.
+func (sd *StructData) WherePrimaryKey() SQLWriter {
+	if sd.err != nil {
+		return &errorWriter{err: sd.err}
+	}
+
+	var conditions []SQLWriter
+	for _, sf := range sd.si.fields {
+		if sf.tagged && sf.primaryKey {
+			conditions = append(conditions, Eq(Col(sf.column), sd.v.FieldByIndex(sf.index).Interface()))
+		}
+	}
+
+	if len(conditions) == 0 {
+		return &errorWriter{err: errors.New("pgsql: " + sd.v.Type().String() + " has no fields tagged with pk")}
+	}
+
+	return And(conditions...)
+}
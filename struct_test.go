@@ -0,0 +1,65 @@
+package pgsql_test
+
+import (
+	"testing"
+
+	"github.com/jackc/pgsql"
+	"github.com/stretchr/testify/assert"
+)
+
+type structAudit struct {
+	CreatedAt string `db:"created_at,readonly,insertdefault"`
+}
+
+type structPerson struct {
+	ID       int64  `db:"id,pk,insertdefault"`
+	Name     string `db:"name"`
+	Nickname string `db:"nickname,omitempty"`
+	Age      int32  `db:"age"`
+	Note     string
+	structAudit
+}
+
+func TestStructInsertData(t *testing.T) {
+	a := pgsql.Insert("people").Data(pgsql.Struct(&structPerson{Name: "Alice", Age: 30})).Returning("id")
+	sql, args := pgsql.Build(a)
+	assert.Equal(t, "insert into people (id, name, age, created_at) values (default,$1,$2,default) returning id", sql)
+	assert.Equal(t, []interface{}{"Alice", int32(30)}, args)
+
+	a = pgsql.Insert("people").Data(pgsql.Struct(structPerson{ID: 7, Name: "Bob", Nickname: "B", structAudit: structAudit{CreatedAt: "2022-01-01"}}))
+	sql, args = pgsql.Build(a)
+	assert.Equal(t, "insert into people (id, name, nickname, age, created_at) values ($1,$2,$3,$4,$5)", sql)
+	assert.Equal(t, []interface{}{int64(7), "Bob", "B", int32(0), "2022-01-01"}, args)
+}
+
+func TestStructUpdateData(t *testing.T) {
+	p := pgsql.Struct(&structPerson{ID: 7, Name: "Alice", Age: 30, structAudit: structAudit{CreatedAt: "2022-01-01"}})
+	a := pgsql.Update("people").Set(p).WhereExpr(p.WherePrimaryKey())
+	sql, args := pgsql.Build(a)
+	assert.Equal(t, "update people set name = $1, age = $2 where (id = $3)", sql)
+	assert.Equal(t, []interface{}{"Alice", int32(30), int64(7)}, args)
+}
+
+func TestStructCompositePrimaryKey(t *testing.T) {
+	type membership struct {
+		TeamID   int64  `db:"team_id,pk"`
+		PersonID int64  `db:"person_id,pk"`
+		Role     string `db:"role"`
+	}
+
+	m := pgsql.Struct(membership{TeamID: 1, PersonID: 2, Role: "owner"})
+	sql, args := pgsql.Build(pgsql.Delete("memberships").WhereExpr(m.WherePrimaryKey()))
+	assert.Equal(t, "delete from memberships where ((team_id = $1) and (person_id = $2))", sql)
+	assert.Equal(t, []interface{}{int64(1), int64(2)}, args)
+}
+
+func TestStructErrors(t *testing.T) {
+	_, _, err := pgsql.BuildE(pgsql.Insert("people").Data(pgsql.Struct(42)))
+	assert.EqualError(t, err, "pgsql: Struct requires a struct or pointer to a struct, got int")
+
+	_, _, err = pgsql.BuildE(pgsql.Update("people").Set(pgsql.Struct(42)))
+	assert.EqualError(t, err, "pgsql: Struct requires a struct or pointer to a struct, got int")
+
+	_, _, err = pgsql.BuildE(pgsql.Update("people").Setf("a = 1").WhereExpr(pgsql.Struct(struct{}{}).WherePrimaryKey()))
+	assert.EqualError(t, err, "pgsql: struct {} has no fields tagged with pk")
+}
@@ -0,0 +1,116 @@
+package pgsql
+
+import (
+	"errors"
+	"strings"
+)
+
+// Subquery is a parenthesized statement for use as a from item or expression. It is created by Sub.
+type Subquery struct {
+	stmt    SQLWriter
+	alias   string
+	columns []string
+}
+
+// Sub returns stmt as a subquery. Placeholders in stmt are numbered together with the statement it is written into.
+// e.g. From(Sub(Select("id").From("people").Where("age > ?", 21)).As("adults")).
+func Sub(stmt SQLWriter) *Subquery {
+	return &Subquery{stmt: stmt}
+}
+
+// As sets the alias and optional column aliases of the subquery. e.g. Sub(s).As("t", "a", "b") is written as
+// "(...) as t(a, b)".
+func (sq *Subquery) As(alias string, columns ...string) *Subquery {
+	sq.alias = alias
+	sq.columns = columns
+	return sq
+}
+
+func (sq *Subquery) WriteSQL(sb *strings.Builder, args *Args) {
+	if isNilSQLWriter(sq.stmt) {
+		args.SetError(errors.New("pgsql: subquery is nil"))
+		return
+	}
+
+	sb.WriteByte('(')
+	sq.stmt.WriteSQL(sb, args)
+	sb.WriteByte(')')
+
+	if sq.alias != "" {
+		sb.WriteString(" as ")
+		writeName(sb, args, sq.alias)
+		if len(sq.columns) > 0 {
+			sb.WriteByte('(')
+			nameList(sq.columns).WriteSQL(sb, args)
+			sb.WriteByte(')')
+		}
+	}
+}
+
+// Scalar returns stmt as a scalar subquery for use as an expression operand. e.g. Gt(Col("price"),
+// Scalar(Select("avg(price)").From("products"))).
+func Scalar(stmt SQLWriter) SQLWriter {
+	return &Subquery{stmt: stmt}
+}
+
+type existsExpr struct {
+	not  bool
+	stmt SQLWriter
+}
+
+func (ee *existsExpr) WriteSQL(sb *strings.Builder, args *Args) {
+	stmt := unwrapSubquery(ee.stmt)
+	if isNilSQLWriter(stmt) {
+		args.SetError(errors.New("pgsql: exists subquery is nil"))
+		return
+	}
+
+	if ee.not {
+		sb.WriteString("not ")
+	}
+	sb.WriteString("exists (")
+	stmt.WriteSQL(sb, args)
+	sb.WriteByte(')')
+}
+
+func (ee *existsExpr) isParenthesized() bool { return true }
+
+// Exists returns an expression that tests if stmt returns any rows.
+func Exists(stmt SQLWriter) SQLWriter {
+	return &existsExpr{stmt: stmt}
+}
+
+// NotExists returns an expression that tests if stmt returns no rows.
+func NotExists(stmt SQLWriter) SQLWriter {
+	return &existsExpr{not: true, stmt: stmt}
+}
+
+// isStatement reports whether w is a query that must be parenthesized when it is nested in another statement.
+func isStatement(w SQLWriter) bool {
+	switch w.(type) {
+	case *SelectStatement, *CompoundStatement, *ValuesStatement:
+		return true
+	default:
+		return false
+	}
+}
+
+// unwrapSubquery returns the statement of w if it is an unaliased Subquery. Otherwise it returns w. It allows a
+// Subquery to be used where the parentheses are already written.
+func unwrapSubquery(w SQLWriter) SQLWriter {
+	if sq, ok := w.(*Subquery); ok && sq.alias == "" {
+		return sq.stmt
+	}
+	return w
+}
+
+// newFromItem returns a SQLWriter for a from or using item. v may be a format string with args or a SQLWriter.
+// PostgreSQL before 16 requires a subquery in from to have an alias, so a statement or a Subquery without an alias is
+// an error.
+func newFromItem(v interface{}, args []interface{}) SQLWriter {
+	w := newExpr("from item", v, args)
+	if isStatement(w) || w != unwrapSubquery(w) {
+		return &errorWriter{err: errors.New("pgsql: subquery from item requires an alias, use Sub(stmt).As(alias)")}
+	}
+	return w
+}
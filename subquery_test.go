@@ -0,0 +1,113 @@
+package pgsql_test
+
+import (
+	"testing"
+
+	"github.com/jackc/pgsql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormatSQLWriterArg(t *testing.T) {
+	sub := pgsql.Select("team_id").From("memberships").Where("role = ?", "admin")
+	ss := pgsql.Select("*").From("teams").Where("active = ?", true).Where("id in ?", sub).Where("name <> ?", "")
+
+	sql, args := pgsql.Build(ss)
+	assert.Equal(t, "select * from teams where (active = $1) and (id in (select team_id from memberships where (role = $2))) and (name <> $3)", sql)
+	assert.Equal(t, []interface{}{true, "admin", ""}, args)
+}
+
+func TestFormatSQLWriterArgNotParenthesized(t *testing.T) {
+	sql, args := pgsql.Build(pgsql.Select("?, ?", pgsql.Col("order"), pgsql.Eq(pgsql.Col("a"), 1)))
+	assert.Equal(t, `select "order", (a = $1)`, sql)
+	assert.Equal(t, []interface{}{1}, args)
+}
+
+func TestFormatSQLWriterNamedArg(t *testing.T) {
+	sub := pgsql.Select("max(score)").From("scores").Where("game_id = ?", 7)
+	ss := pgsql.Select("*").From("scores").Where("score = :best and game_id = :game", pgsql.Named(map[string]interface{}{
+		"best": sub,
+		"game": 7,
+	}))
+
+	sql, args := pgsql.Build(ss)
+	assert.Equal(t, "select * from scores where (score = (select max(score) from scores where (game_id = $1)) and game_id = $2)", sql)
+	assert.Equal(t, []interface{}{7, 7}, args)
+}
+
+func TestFormatNilSQLWriterArg(t *testing.T) {
+	var sub *pgsql.SelectStatement
+	_, _, err := pgsql.BuildE(pgsql.Select("*").Where("id in ?", sub))
+	require.EqualError(t, err, "pgsql: SQLWriter argument is nil")
+}
+
+func TestSelectStatementFromSub(t *testing.T) {
+	sub := pgsql.Select("id, name").From("people").Where("age > ?", 21)
+	ss := pgsql.Select("a.name").From(pgsql.Sub(sub).As("a", "person_id", "name")).Where("a.person_id <> ?", 3)
+
+	sql, args := pgsql.Build(ss)
+	assert.Equal(t, "select a.name from (select id, name from people where (age > $1)) as a(person_id, name) where (a.person_id <> $2)", sql)
+	assert.Equal(t, []interface{}{21, 3}, args)
+
+	_, _, err := pgsql.BuildE(pgsql.Select("count(*)").From(pgsql.Select("1")))
+	assert.EqualError(t, err, "pgsql: subquery from item requires an alias, use Sub(stmt).As(alias)")
+
+	_, _, err = pgsql.BuildE(pgsql.Select("count(*)").From(pgsql.Sub(pgsql.Select("1"))))
+	assert.EqualError(t, err, "pgsql: subquery from item requires an alias, use Sub(stmt).As(alias)")
+
+	_, _, err = pgsql.BuildE(pgsql.Delete("people").Using(pgsql.Values().Row(1)))
+	assert.EqualError(t, err, "pgsql: subquery from item requires an alias, use Sub(stmt).As(alias)")
+
+	sql, args = pgsql.Build(pgsql.Select("*").From("? as t(n)", pgsql.Values().Row(1).Row(2)))
+	assert.Equal(t, "select * from (values ($1), ($2)) as t(n)", sql)
+	assert.Equal(t, []interface{}{1, 2}, args)
+}
+
+func TestUpdateAndDeleteFromSub(t *testing.T) {
+	totals := pgsql.Select("person_id, sum(amount) as total").From("orders").Where("status = ?", "paid").GroupBy("person_id")
+
+	us := pgsql.Update("people").Setf("total = t.total").From(pgsql.Sub(totals).As("t")).Where("t.person_id = people.id")
+	sql, args := pgsql.Build(us)
+	assert.Equal(t, "update people set total = t.total from (select person_id, sum(amount) as total from orders where (status = $1) group by person_id) as t where (t.person_id = people.id)", sql)
+	assert.Equal(t, []interface{}{"paid"}, args)
+
+	ds := pgsql.Delete("people").Using(pgsql.Sub(totals).As("t")).Where("t.person_id = people.id").Where("t.total < ?", 0)
+	sql, args = pgsql.Build(ds)
+	assert.Equal(t, "delete from people using (select person_id, sum(amount) as total from orders where (status = $1) group by person_id) as t where (t.person_id = people.id) and (t.total < $2)", sql)
+	assert.Equal(t, []interface{}{"paid", 0}, args)
+}
+
+func TestExists(t *testing.T) {
+	orders := pgsql.Select("1").From("orders").Where("orders.person_id = people.id").Where("orders.total > ?", 100)
+	ss := pgsql.Select("*").From("people").WhereExpr(pgsql.Exists(orders)).WhereExpr(pgsql.NotExists(pgsql.Sub(pgsql.Select("1").From("bans").Where("bans.person_id = people.id"))))
+
+	sql, args := pgsql.Build(ss)
+	assert.Equal(t, "select * from people where exists (select 1 from orders where (orders.person_id = people.id) and (orders.total > $1)) and not exists (select 1 from bans where (bans.person_id = people.id))", sql)
+	assert.Equal(t, []interface{}{100}, args)
+}
+
+func TestScalar(t *testing.T) {
+	avg := pgsql.Select("avg(price)").From("products").Where("category = ?", "tools")
+	ss := pgsql.Select("name").Select("? as avg_price", pgsql.Scalar(avg)).From("products").WhereExpr(pgsql.Gt(pgsql.Col("price"), pgsql.Scalar(avg)))
+
+	sql, args := pgsql.Build(ss)
+	assert.Equal(t, "select name, (select avg(price) from products where (category = $1)) as avg_price from products where (price > (select avg(price) from products where (category = $2)))", sql)
+	assert.Equal(t, []interface{}{"tools", "tools"}, args)
+}
+
+func TestInSub(t *testing.T) {
+	sub := pgsql.Sub(pgsql.Select("id").From("admins"))
+	sql, _ := pgsql.Build(pgsql.Select("*").From("people").WhereExpr(pgsql.In(pgsql.Col("id"), sub)))
+	assert.Equal(t, "select * from people where (id in (select id from admins))", sql)
+}
+
+func TestSubErrors(t *testing.T) {
+	_, _, err := pgsql.BuildE(pgsql.Select("*").From(pgsql.Sub(nil).As("t")))
+	assert.EqualError(t, err, "pgsql: subquery is nil")
+
+	_, _, err = pgsql.BuildE(pgsql.Select("*").WhereExpr(pgsql.Exists(nil)))
+	assert.EqualError(t, err, "pgsql: exists subquery is nil")
+
+	_, _, err = pgsql.BuildE(pgsql.Select("*").From(42))
+	assert.EqualError(t, err, "pgsql: unsupported from item type int")
+}
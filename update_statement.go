@@ -1,6 +1,7 @@
 package pgsql
 
 import (
+	"errors"
 	"strings"
 )
 
@@ -16,73 +17,249 @@ func (a Assignments) UpdateData() []*Assignment {
 }
 
 type UpdateStatement struct {
+	withList      withList
 	tableName     string
+	tableNameErr  error
 	setf          *FormatString
 	assignments   []*Assignment
+	from          SQLWriter
 	whereList     whereList
 	returningList returningList
+
+	frozen bool
 }
 
-func Update(tableName string) *UpdateStatement {
-	return &UpdateStatement{tableName: tableName}
+// Update returns a statement that updates tableName. tableName may be a string or an Ident. A string may include an
+// alias such as "people p".
+func Update(tableName interface{}) *UpdateStatement {
+	name, err := nameOf("update table name", tableName)
+	return &UpdateStatement{tableName: name, tableNameErr: err}
 }
 
 func (us *UpdateStatement) UpdateStatement() (*UpdateStatement, error) {
 	return us, nil
 }
 
+func (us *UpdateStatement) With(name string, query SQLWriter) *UpdateStatement {
+	us = us.mutable()
+	us.withList = append(us.withList, &CommonTableExpression{Name: name, Query: query})
+	return us
+}
+
+func (us *UpdateStatement) WithRecursive(name string, query SQLWriter) *UpdateStatement {
+	us = us.mutable()
+	us.withList = append(us.withList, &CommonTableExpression{Name: name, Recursive: true, Query: query})
+	return us
+}
+
+// WithCTE adds cte to the with clause. Use this instead of With when a column list or materialization is needed.
+func (us *UpdateStatement) WithCTE(cte *CommonTableExpression) *UpdateStatement {
+	us = us.mutable()
+	us.withList = append(us.withList, cte)
+	return us
+}
+
 type Updateable interface {
 	UpdateData() []*Assignment
 }
 
 func (us *UpdateStatement) Set(data Updateable) *UpdateStatement {
+	us = us.mutable()
 	us.assignments = data.UpdateData()
 	us.setf = nil
 	return us
 }
 
 func (us *UpdateStatement) Setf(s string, args ...interface{}) *UpdateStatement {
+	us = us.mutable()
 	us.setf = &FormatString{s: s, args: args}
 	us.assignments = nil
 	return us
 }
 
+// From sets the from item that supplies additional tables to the update. e.g. From("teams t") with
+// Where("t.id = people.team_id"). from may also be a SQLWriter such as Sub(stmt).As("t").
+func (us *UpdateStatement) From(from interface{}, args ...interface{}) *UpdateStatement {
+	us = us.mutable()
+	us.from = newFromItem(from, args)
+	return us
+}
+
+// Where adds a condition to the where clause. Multiple conditions are combined with and.
 func (us *UpdateStatement) Where(s string, args ...interface{}) *UpdateStatement {
+	us = us.mutable()
 	us.whereList = append(us.whereList, &FormatString{s: s, args: args})
 	return us
 }
 
+// WhereExpr adds an expression such as Eq(Col("id"), 42) to the where clause.
+func (us *UpdateStatement) WhereExpr(expr SQLWriter) *UpdateStatement {
+	us = us.mutable()
+	us.whereList = append(us.whereList, checkExpr("condition", expr))
+	return us
+}
+
 func (us *UpdateStatement) Returning(s string, args ...interface{}) *UpdateStatement {
+	us = us.mutable()
 	us.returningList = append(us.returningList, &FormatString{s: s, args: args})
 	return us
 }
 
+// Clone returns a copy of us that can be modified without affecting us. Common table expressions and a from item that
+// is a statement or subquery are copied too. Other SQLWriters are shared. The copy is not frozen.
+func (us *UpdateStatement) Clone() *UpdateStatement {
+	c := *us
+	c.withList = us.withList.clone()
+	c.from = cloneStatement(us.from)
+	c.assignments = cloneSlice(us.assignments)
+	c.whereList = cloneSlice(us.whereList)
+	c.returningList = cloneSlice(us.returningList)
+	c.frozen = false
+	return &c
+}
+
+// Freeze makes us copy-on-write. See SelectStatement.Freeze.
+func (us *UpdateStatement) Freeze() *UpdateStatement {
+	us.frozen = true
+	return us
+}
+
+func (us *UpdateStatement) mutable() *UpdateStatement {
+	if us.frozen {
+		return us.Clone()
+	}
+	return us
+}
+
 func (us *UpdateStatement) WriteSQL(sb *strings.Builder, args *Args) {
+	if us.tableNameErr != nil {
+		args.SetError(us.tableNameErr)
+	} else if us.tableName == "" {
+		args.SetError(errors.New("pgsql: update table name is empty"))
+	}
+	if us.setf == nil && len(us.assignments) == 0 {
+		args.SetError(errors.New("pgsql: update statement has no assignments"))
+	}
+
+	us.withList.WriteSQL(sb, args)
 	sb.WriteString("update ")
-	sb.WriteString(us.tableName)
+	writeTableName(sb, args, us.tableName)
 	sb.WriteString(" set ")
 
 	if us.setf != nil {
 		us.setf.WriteSQL(sb, args)
 	} else {
-		for i, a := range us.assignments {
-			if i > 0 {
-				sb.WriteString(", ")
-			}
-			a.Left.WriteSQL(sb, args)
-			sb.WriteString(" = ")
-			a.Right.WriteSQL(sb, args)
-		}
+		writeAssignments(sb, args, us.assignments)
+	}
+
+	if us.from != nil {
+		sb.WriteString(" from ")
+		us.from.WriteSQL(sb, args)
 	}
 
 	us.whereList.WriteSQL(sb, args)
 	us.returningList.WriteSQL(sb, args)
 }
 
+func writeAssignments(sb *strings.Builder, args *Args, assignments []*Assignment) {
+	for i, a := range assignments {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		a.Left.WriteSQL(sb, args)
+		sb.WriteString(" = ")
+		a.Right.WriteSQL(sb, args)
+	}
+}
+
 func (us *UpdateStatement) Apply(others ...*SelectStatement) *UpdateStatement {
+	us = us.mutable()
 	for _, other := range others {
+		us.withList = append(us.withList, other.withList...)
 		us.whereList = append(us.whereList, other.whereList...)
 	}
 
 	return us
 }
+
+// BulkUpdate returns a statement that updates many rows of tableName with different values in a single statement. Each
+// row of vs contains the values of keyColumns followed by the values of valueColumns. Rows of tableName that match the
+// key columns are updated with the value columns. The values list is aliased as v. Use ValuesStatement.Types to cast
+// the values when their types cannot be inferred. tableName may be a string or an Ident. A string may include an alias
+// such as "people p". At least one key column is required.
+//
+// e.g. BulkUpdate("people", Values().Row(1, "Alice").Row(2, "Bob"), []string{"id"}, []string{"name"}) is rendered as
+// "update people set name = v.name from (values ($1,$2), ($3,$4)) as v(id, name) where (people.id = v.id)".
+func BulkUpdate(tableName interface{}, vs *ValuesStatement, keyColumns, valueColumns []string) *UpdateStatement {
+	const alias = "v"
+
+	assignments := make(Assignments, len(valueColumns))
+	for i, c := range valueColumns {
+		assignments[i] = &Assignment{Left: Column(c), Right: Column(alias + "." + c)}
+	}
+
+	columns := make([]string, 0, len(keyColumns)+len(valueColumns))
+	columns = append(columns, keyColumns...)
+	columns = append(columns, valueColumns...)
+
+	us := Update(tableName).Set(assignments)
+	us.from = &aliasedValues{values: vs, alias: alias, columns: columns}
+
+	if len(keyColumns) == 0 {
+		us.whereList = append(us.whereList, &errorWriter{err: errors.New("pgsql: bulk update requires at least one key column")})
+		return us
+	}
+
+	qualifier := tableQualifier(us.tableName)
+	if qualifier == alias {
+		us.whereList = append(us.whereList, &errorWriter{err: errors.New("pgsql: bulk update table alias must not be v")})
+		return us
+	}
+
+	for _, c := range keyColumns {
+		us.whereList = append(us.whereList, &columnsEqual{left: qualifier + "." + c, right: alias + "." + c})
+	}
+
+	return us
+}
+
+// tableQualifier returns the name that refers to the table of a "name", "name alias" or "name as alias" table
+// reference.
+func tableQualifier(tableName string) string {
+	if _, _, alias, ok := splitTableAlias(tableName); ok {
+		return alias
+	}
+	return tableName
+}
+
+type aliasedValues struct {
+	values  *ValuesStatement
+	alias   string
+	columns []string
+}
+
+func (av *aliasedValues) WriteSQL(sb *strings.Builder, args *Args) {
+	if av.values == nil {
+		args.SetError(errors.New("pgsql: values statement is nil"))
+		return
+	}
+
+	sb.WriteByte('(')
+	av.values.WriteSQL(sb, args)
+	sb.WriteString(") as ")
+	writeName(sb, args, av.alias)
+	sb.WriteByte('(')
+	nameList(av.columns).WriteSQL(sb, args)
+	sb.WriteByte(')')
+}
+
+type columnsEqual struct {
+	left  string
+	right string
+}
+
+func (ce *columnsEqual) WriteSQL(sb *strings.Builder, args *Args) {
+	writeName(sb, args, ce.left)
+	sb.WriteString(" = ")
+	writeName(sb, args, ce.right)
+}
@@ -60,3 +60,60 @@ func TestUpdateStatementMerge(t *testing.T) {
 	assert.Equal(t, `update people set age = $1, name = $2 where (id=$3) and (foo=$4)`, sql)
 	assert.Equal(t, []interface{}{30, "Alice", 42, 43}, args)
 }
+
+func TestUpdateStatementFrom(t *testing.T) {
+	a := pgsql.Update("people").Setf("team_name = t.name").From("teams t").Where("t.id = people.team_id and t.region = ?", "west")
+	a.Returning("people.id")
+	sql, args := pgsql.Build(a)
+	assert.Equal(t, "update people set team_name = t.name from teams t where (t.id = people.team_id and t.region = $1) returning people.id", sql)
+	assert.Equal(t, []interface{}{"west"}, args)
+}
+
+func TestBulkUpdate(t *testing.T) {
+	vs := pgsql.Values().Row(1, "Alice", 30).Row(2, "Bob", 32).Types("int8", "text", "int4")
+	a := pgsql.BulkUpdate("people", vs, []string{"id"}, []string{"name", "age"})
+	a.Where("people.locked = ?", false)
+	sql, args := pgsql.Build(a)
+	assert.Equal(t, "update people set name = v.name, age = v.age from (values ($1::int8,$2::text,$3::int4), ($4,$5,$6)) as v(id, name, age) where (people.id = v.id) and (people.locked = $7)", sql)
+	assert.Equal(t, []interface{}{1, "Alice", 30, 2, "Bob", 32, false}, args)
+}
+
+func TestBulkUpdateCompositeKey(t *testing.T) {
+	vs := pgsql.Values().Row(1, 2, "owner").Types("", "", "text")
+	a := pgsql.BulkUpdate("memberships", vs, []string{"team_id", "person_id"}, []string{"role"})
+	sql, args := pgsql.Build(a)
+	assert.Equal(t, "update memberships set role = v.role from (values ($1,$2,$3::text)) as v(team_id, person_id, role) where (memberships.team_id = v.team_id) and (memberships.person_id = v.person_id)", sql)
+	assert.Equal(t, []interface{}{1, 2, "owner"}, args)
+}
+
+func TestBulkUpdateAliasedTable(t *testing.T) {
+	vs := pgsql.Values().Row(1, "Alice")
+	for _, tableName := range []string{"people p", "people as p"} {
+		sql, _ := pgsql.Build(pgsql.BulkUpdate(tableName, vs, []string{"id"}, []string{"name"}))
+		assert.Equal(t, "update "+tableName+" set name = v.name from (values ($1,$2)) as v(id, name) where (p.id = v.id)", sql)
+	}
+}
+
+func TestBulkUpdateErrors(t *testing.T) {
+	vs := pgsql.Values().Row(1, "Alice")
+
+	_, _, err := pgsql.BuildE(pgsql.BulkUpdate("people", vs, nil, []string{"id", "name"}))
+	assert.EqualError(t, err, "pgsql: bulk update requires at least one key column")
+
+	_, _, err = pgsql.BuildE(pgsql.BulkUpdate("people v", vs, []string{"id"}, []string{"name"}))
+	assert.EqualError(t, err, "pgsql: bulk update table alias must not be v")
+}
+
+func TestUpdateStatementCloneCopiesNestedStatements(t *testing.T) {
+	teams := pgsql.Select("id, name").From("teams")
+	base := pgsql.Update("people").Setf("team_name = t.name").From(pgsql.Sub(teams).As("t")).Where("t.id = people.team_id")
+	c := base.Clone()
+	teams.Where("region = ?", "west")
+
+	sql, args := pgsql.Build(c)
+	assert.Equal(t, "update people set team_name = t.name from (select id, name from teams) as t where (t.id = people.team_id)", sql)
+	assert.Empty(t, args)
+
+	sql, _ = pgsql.Build(base)
+	assert.Equal(t, "update people set team_name = t.name from (select id, name from teams where (region = $1)) as t where (t.id = people.team_id)", sql)
+}
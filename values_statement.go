@@ -1,11 +1,15 @@
 package pgsql
 
 import (
+	"errors"
 	"strings"
 )
 
 type ValuesStatement struct {
-	rows [][]SQLWriter
+	rows  [][]SQLWriter
+	types []string
+
+	frozen bool
 }
 
 func Values() *ValuesStatement {
@@ -13,14 +17,10 @@ func Values() *ValuesStatement {
 }
 
 func (vs *ValuesStatement) Row(values ...interface{}) *ValuesStatement {
+	vs = vs.mutable()
 	row := make([]SQLWriter, len(values))
 	for i := range values {
-		in := values[i]
-		out, ok := in.(SQLWriter)
-		if !ok {
-			out = &Param{Value: in}
-		}
-		row[i] = out
+		row[i] = toSQLWriter(values[i])
 	}
 
 	vs.rows = append(vs.rows, row)
@@ -28,7 +28,56 @@ func (vs *ValuesStatement) Row(values ...interface{}) *ValuesStatement {
 	return vs
 }
 
+// Types sets the type of each column. The values of the first row are cast to these types which determines the column
+// types of the entire values list. An empty string leaves a column uncast. Only parameters are cast. Other values in
+// the first row such as default or an expression are written unchanged.
+func (vs *ValuesStatement) Types(types ...string) *ValuesStatement {
+	vs = vs.mutable()
+	vs.types = types
+	return vs
+}
+
+// Clone returns a copy of vs that can be modified without affecting vs. The copy is not frozen.
+func (vs *ValuesStatement) Clone() *ValuesStatement {
+	c := *vs
+	c.rows = cloneSlice(vs.rows)
+	c.types = cloneSlice(vs.types)
+	c.frozen = false
+	return &c
+}
+
+// Freeze makes vs copy-on-write. See SelectStatement.Freeze.
+func (vs *ValuesStatement) Freeze() *ValuesStatement {
+	vs.frozen = true
+	return vs
+}
+
+func (vs *ValuesStatement) mutable() *ValuesStatement {
+	if vs.frozen {
+		return vs.Clone()
+	}
+	return vs
+}
+
+func isParam(w SQLWriter) bool {
+	switch w.(type) {
+	case Param, *Param:
+		return true
+	default:
+		return false
+	}
+}
+
 func (vs *ValuesStatement) WriteSQL(sb *strings.Builder, args *Args) {
+	if len(vs.rows) == 0 {
+		args.SetError(errors.New("pgsql: values statement has no rows"))
+	}
+	for _, row := range vs.rows {
+		if len(row) != len(vs.rows[0]) {
+			args.SetError(errors.New("pgsql: values statement rows must have the same number of values"))
+		}
+	}
+
 	sb.WriteString("values ")
 	for i, row := range vs.rows {
 		if i > 0 {
@@ -40,6 +89,10 @@ func (vs *ValuesStatement) WriteSQL(sb *strings.Builder, args *Args) {
 				sb.WriteByte(',')
 			}
 			v.WriteSQL(sb, args)
+			if i == 0 && j < len(vs.types) && vs.types[j] != "" && isParam(v) {
+				sb.WriteString("::")
+				sb.WriteString(vs.types[j])
+			}
 		}
 		sb.WriteByte(')')
 	}
@@ -25,3 +25,29 @@ func TestValuesStatementMultipleRows(t *testing.T) {
 	assert.Equal(t, "values ($1,$2), ($3,$4)", sql)
 	assert.Equal(t, []interface{}{"a", "b", "c", "d"}, args)
 }
+
+func TestValuesStatementTypes(t *testing.T) {
+	v := pgsql.Values().Row("a", 1, true).Row("b", 2, false).Types("text", "", "bool")
+
+	sql, args := pgsql.Build(v)
+	assert.Equal(t, "values ($1::text,$2,$3::bool), ($4,$5,$6)", sql)
+	assert.Equal(t, []interface{}{"a", 1, true, "b", 2, false}, args)
+}
+
+func TestValuesStatementTypesOnlyCastsParams(t *testing.T) {
+	v := pgsql.Values().Row(pgsql.Col("default"), pgsql.Param{Value: 1}, pgsql.Eq(pgsql.Col("a"), 2)).Row(3, 4, true).Types("int8", "int4", "bool")
+
+	sql, args := pgsql.Build(v)
+	assert.Equal(t, `values ("default",$1::int4,(a = $2)), ($3,$4,$5)`, sql)
+	assert.Equal(t, []interface{}{1, 2, 3, 4, true}, args)
+}
+
+func TestValuesStatementCloneDoesNotShareRows(t *testing.T) {
+	base := pgsql.Values().Row(1, "a")
+	c := base.Clone()
+	base.Row(2, "b").Types("int4", "text")
+
+	sql, args := pgsql.Build(c)
+	assert.Equal(t, "values ($1,$2)", sql)
+	assert.Equal(t, []interface{}{1, "a"}, args)
+}
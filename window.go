@@ -0,0 +1,216 @@
+package pgsql
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// WindowDefinition is a window specification for window function calls and the window clause. It is rendered in
+// parentheses. e.g. "(partition by dept order by salary desc rows between unbounded preceding and current row)".
+type WindowDefinition struct {
+	base            string
+	partitionByList []SQLWriter
+	orderByList     []SQLWriter
+	frame           SQLWriter
+	exclude         string
+}
+
+// WindowDef returns an empty window definition.
+func WindowDef() *WindowDefinition {
+	return &WindowDefinition{}
+}
+
+// Base sets the existing window name the definition builds on. e.g. WindowDef().Base("w").Order("ts") is rendered as
+// "(w order by ts)".
+func (wd *WindowDefinition) Base(name string) *WindowDefinition {
+	wd.base = name
+	return wd
+}
+
+func (wd *WindowDefinition) PartitionBy(s string, args ...interface{}) *WindowDefinition {
+	wd.partitionByList = append(wd.partitionByList, &FormatString{s: s, args: args})
+	return wd
+}
+
+func (wd *WindowDefinition) Order(s string, args ...interface{}) *WindowDefinition {
+	wd.orderByList = append(wd.orderByList, &FormatString{s: s, args: args})
+	return wd
+}
+
+// Rows sets a rows frame from start to end. start and end are frame bounds such as "unbounded preceding",
+// "? preceding", "current row" or "unbounded following". If end is empty only start is rendered. args are consumed by
+// the placeholders in start and then end.
+func (wd *WindowDefinition) Rows(start, end string, args ...interface{}) *WindowDefinition {
+	return wd.setFrame("rows", start, end, args)
+}
+
+// Range sets a range frame. It accepts the same arguments as Rows.
+func (wd *WindowDefinition) Range(start, end string, args ...interface{}) *WindowDefinition {
+	return wd.setFrame("range", start, end, args)
+}
+
+// Groups sets a groups frame. It accepts the same arguments as Rows.
+func (wd *WindowDefinition) Groups(start, end string, args ...interface{}) *WindowDefinition {
+	return wd.setFrame("groups", start, end, args)
+}
+
+func (wd *WindowDefinition) setFrame(mode, start, end string, args []interface{}) *WindowDefinition {
+	s := mode + " " + start
+	if end != "" {
+		s = mode + " between " + start + " and " + end
+	}
+	wd.frame = &FormatString{s: s, args: args}
+	return wd
+}
+
+// Exclude sets the frame exclusion. exclusion must be one of "current row", "group", "ties" or "no others". It
+// requires a frame set with Rows, Range or Groups.
+func (wd *WindowDefinition) Exclude(exclusion string) *WindowDefinition {
+	wd.exclude = exclusion
+	return wd
+}
+
+func (wd *WindowDefinition) WriteSQL(sb *strings.Builder, args *Args) {
+	sb.WriteByte('(')
+	needSpace := false
+	writeSpace := func() {
+		if needSpace {
+			sb.WriteByte(' ')
+		}
+		needSpace = true
+	}
+
+	if wd.base != "" {
+		writeSpace()
+		writeName(sb, args, wd.base)
+	}
+
+	if len(wd.partitionByList) > 0 {
+		writeSpace()
+		sb.WriteString("partition by ")
+		for i, e := range wd.partitionByList {
+			if i > 0 {
+				sb.WriteString(", ")
+			}
+			e.WriteSQL(sb, args)
+		}
+	}
+
+	if len(wd.orderByList) > 0 {
+		writeSpace()
+		sb.WriteString("order by ")
+		for i, e := range wd.orderByList {
+			if i > 0 {
+				sb.WriteString(", ")
+			}
+			e.WriteSQL(sb, args)
+		}
+	}
+
+	if wd.frame != nil {
+		writeSpace()
+		wd.frame.WriteSQL(sb, args)
+	}
+
+	if wd.exclude != "" {
+		switch wd.exclude {
+		case "current row", "group", "ties", "no others":
+		default:
+			args.SetError(fmt.Errorf("pgsql: invalid frame exclusion %q", wd.exclude))
+		}
+		if wd.frame == nil {
+			args.SetError(errors.New("pgsql: frame exclusion requires Rows, Range or Groups"))
+		}
+		writeSpace()
+		sb.WriteString("exclude ")
+		sb.WriteString(wd.exclude)
+	}
+
+	sb.WriteByte(')')
+}
+
+// WindowFunction is a window function call for use in a select list. e.g.
+//
+//	WindowFunc("row_number()").Over(WindowDef().PartitionBy("dept").Order("salary desc")).As("rank")
+type WindowFunction struct {
+	fn         SQLWriter
+	def        *WindowDefinition
+	windowName string
+	alias      string
+}
+
+// WindowFunc returns a window function call of s. s is a function call such as "row_number()" or "lag(price, ?)".
+func WindowFunc(s string, args ...interface{}) *WindowFunction {
+	return &WindowFunction{fn: &FormatString{s: s, args: args}}
+}
+
+// Over sets the window of the call to the inline definition def.
+func (wf *WindowFunction) Over(def *WindowDefinition) *WindowFunction {
+	wf.def = def
+	wf.windowName = ""
+	return wf
+}
+
+// OverWindow sets the window of the call to a window named in the window clause. See SelectStatement.Window.
+func (wf *WindowFunction) OverWindow(name string) *WindowFunction {
+	wf.windowName = name
+	wf.def = nil
+	return wf
+}
+
+// As sets the output column name of the call.
+func (wf *WindowFunction) As(alias string) *WindowFunction {
+	wf.alias = alias
+	return wf
+}
+
+func (wf *WindowFunction) WriteSQL(sb *strings.Builder, args *Args) {
+	wf.fn.WriteSQL(sb, args)
+	sb.WriteString(" over ")
+	if wf.def != nil {
+		wf.def.WriteSQL(sb, args)
+	} else if wf.windowName != "" {
+		writeName(sb, args, wf.windowName)
+	} else {
+		args.SetError(errors.New("pgsql: window function requires Over or OverWindow"))
+		sb.WriteString("()")
+	}
+
+	if wf.alias != "" {
+		sb.WriteString(" as ")
+		writeName(sb, args, wf.alias)
+	}
+}
+
+type namedWindow struct {
+	name string
+	def  *WindowDefinition
+}
+
+func (nw *namedWindow) WriteSQL(sb *strings.Builder, args *Args) {
+	if nw.name == "" || nw.def == nil {
+		args.SetError(fmt.Errorf("pgsql: window %q must have a name and definition", nw.name))
+		return
+	}
+
+	writeName(sb, args, nw.name)
+	sb.WriteString(" as ")
+	nw.def.WriteSQL(sb, args)
+}
+
+type windowList []*namedWindow
+
+func (wl windowList) WriteSQL(sb *strings.Builder, args *Args) {
+	if len(wl) == 0 {
+		return
+	}
+
+	sb.WriteString(" window ")
+	for i, nw := range wl {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		nw.WriteSQL(sb, args)
+	}
+}
@@ -0,0 +1,115 @@
+package pgsql_test
+
+import (
+	"testing"
+
+	"github.com/jackc/pgsql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWindowFunctionOver(t *testing.T) {
+	ss := pgsql.Select("name").
+		SelectExpr(pgsql.WindowFunc("row_number()").Over(pgsql.WindowDef().PartitionBy("dept").Order("salary desc")).As("rank")).
+		From("employees").
+		Where("active = ?", true)
+
+	sql, args := pgsql.Build(ss)
+	assert.Equal(t, "select name, row_number() over (partition by dept order by salary desc) as rank from employees where (active = $1)", sql)
+	assert.Equal(t, []interface{}{true}, args)
+}
+
+func TestWindowFunctionOverEmptyDefinition(t *testing.T) {
+	sql, args := pgsql.Build(pgsql.SelectExpr(pgsql.WindowFunc("count(*)").Over(pgsql.WindowDef())).From("t"))
+	assert.Equal(t, "select count(*) over () from t", sql)
+	assert.Empty(t, args)
+}
+
+func TestWindowDefinitionFrame(t *testing.T) {
+	tests := []struct {
+		def *pgsql.WindowDefinition
+		sql string
+	}{
+		{
+			def: pgsql.WindowDef().Order("ts").Rows("unbounded preceding", "current row"),
+			sql: "(order by ts rows between unbounded preceding and current row)",
+		},
+		{
+			def: pgsql.WindowDef().Order("ts").Rows("? preceding", "? following", 2, 3),
+			sql: "(order by ts rows between $1 preceding and $2 following)",
+		},
+		{
+			def: pgsql.WindowDef().Order("ts").Range("unbounded preceding", ""),
+			sql: "(order by ts range unbounded preceding)",
+		},
+		{
+			def: pgsql.WindowDef().Order("ts").Groups("current row", "unbounded following").Exclude("ties"),
+			sql: "(order by ts groups between current row and unbounded following exclude ties)",
+		},
+		{
+			def: pgsql.WindowDef().Base("w").Rows("1 preceding", "1 following").Exclude("current row"),
+			sql: "(w rows between 1 preceding and 1 following exclude current row)",
+		},
+	}
+
+	for i, tt := range tests {
+		sql, _ := pgsql.Build(pgsql.SelectExpr(pgsql.WindowFunc("sum(x)").Over(tt.def)))
+		assert.Equalf(t, "select sum(x) over "+tt.sql, sql, "%d", i)
+	}
+}
+
+func TestSelectStatementWindow(t *testing.T) {
+	ss := pgsql.SelectExpr(pgsql.WindowFunc("sum(amount)").OverWindow("w").As("running")).
+		SelectExpr(pgsql.WindowFunc("avg(amount)").Over(pgsql.WindowDef().Base("w").Rows("? preceding", "current row", 6))).
+		From("sales").
+		GroupBy("region, day, amount").
+		Having("count(*) > ?", 1).
+		Window("w", pgsql.WindowDef().PartitionBy("region").Order("day")).
+		Order("region")
+
+	sql, args := pgsql.Build(ss)
+	assert.Equal(t, "select sum(amount) over w as running, avg(amount) over (w rows between $1 preceding and current row) from sales group by region, day, amount having (count(*) > $2) window w as (partition by region order by day) order by region", sql)
+	assert.Equal(t, []interface{}{6, 1}, args)
+}
+
+func TestSelectStatementApplyWindow(t *testing.T) {
+	ss := pgsql.Select("*").From("t").Window("a", pgsql.WindowDef().Order("x"))
+	ss.Apply(pgsql.Window("b", pgsql.WindowDef().PartitionBy("y")))
+
+	sql, _ := pgsql.Build(ss)
+	assert.Equal(t, "select * from t window a as (order by x), b as (partition by y)", sql)
+}
+
+func TestWindowErrors(t *testing.T) {
+	tests := []struct {
+		ss  *pgsql.SelectStatement
+		err string
+	}{
+		{
+			ss:  pgsql.SelectExpr(pgsql.WindowFunc("rank()")),
+			err: "pgsql: window function requires Over or OverWindow",
+		},
+		{
+			ss:  pgsql.SelectExpr(pgsql.WindowFunc("rank()").Over(pgsql.WindowDef().Exclude("ties"))),
+			err: "pgsql: frame exclusion requires Rows, Range or Groups",
+		},
+		{
+			ss:  pgsql.SelectExpr(pgsql.WindowFunc("rank()").Over(pgsql.WindowDef().Rows("current row", "").Exclude("others"))),
+			err: `pgsql: invalid frame exclusion "others"`,
+		},
+		{
+			ss:  pgsql.Select("*").Window("w", nil),
+			err: `pgsql: window "w" must have a name and definition`,
+		},
+		{
+			ss:  pgsql.SelectExpr(nil),
+			err: "pgsql: select expression is nil",
+		},
+	}
+
+	for i, tt := range tests {
+		_, _, err := pgsql.BuildE(tt.ss)
+		require.Errorf(t, err, "%d", i)
+		assert.EqualErrorf(t, err, tt.err, "%d", i)
+	}
+}
@@ -0,0 +1,97 @@
+package pgsql
+
+import (
+	"fmt"
+	"strings"
+)
+
+type Materialization int8
+
+const (
+	MaterializeDefault Materialization = iota
+	Materialized
+	NotMaterialized
+)
+
+// CommonTableExpression is a single entry in a with clause.
+type CommonTableExpression struct {
+	Name    string
+	Columns []string
+
+	// Recursive causes the with clause to be rendered as with recursive. PostgreSQL applies recursive to the entire
+	// with clause so it is sufficient for any expression in the clause to set it.
+	Recursive bool
+
+	Materialized Materialization
+	Query        SQLWriter
+}
+
+func (cte *CommonTableExpression) WriteSQL(sb *strings.Builder, args *Args) {
+	if cte.Name == "" || isNilSQLWriter(cte.Query) {
+		args.SetError(fmt.Errorf("pgsql: common table expression %q must have a name and query", cte.Name))
+		return
+	}
+
+	writeName(sb, args, cte.Name)
+	if len(cte.Columns) > 0 {
+		sb.WriteByte('(')
+		nameList(cte.Columns).WriteSQL(sb, args)
+		sb.WriteByte(')')
+	}
+
+	sb.WriteString(" as ")
+	switch cte.Materialized {
+	case Materialized:
+		sb.WriteString("materialized ")
+	case NotMaterialized:
+		sb.WriteString("not materialized ")
+	}
+
+	sb.WriteByte('(')
+	cte.Query.WriteSQL(sb, args)
+	sb.WriteByte(')')
+}
+
+type withList []*CommonTableExpression
+
+// clone returns a copy of wl with copies of each common table expression and its query.
+func (wl withList) clone() withList {
+	if wl == nil {
+		return nil
+	}
+
+	c := make(withList, len(wl))
+	for i, cte := range wl {
+		if cte == nil {
+			continue
+		}
+		cc := *cte
+		cc.Columns = cloneSlice(cte.Columns)
+		cc.Query = cloneStatement(cte.Query)
+		c[i] = &cc
+	}
+	return c
+}
+
+func (wl withList) WriteSQL(sb *strings.Builder, args *Args) {
+	if len(wl) == 0 {
+		return
+	}
+
+	sb.WriteString("with ")
+	for _, cte := range wl {
+		if cte.Recursive {
+			sb.WriteString("recursive ")
+			break
+		}
+	}
+
+	for i, cte := range wl {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		cte.WriteSQL(sb, args)
+	}
+
+	sb.WriteByte(' ')
+}
@@ -0,0 +1,89 @@
+package pgsql_test
+
+import (
+	"testing"
+
+	"github.com/jackc/pgsql"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSelectStatementWith(t *testing.T) {
+	recent := pgsql.Select("id").From("orders").Where("created_at > ?", "2022-01-01")
+	a := pgsql.Select("*").From("customers").Where("id in (select id from recent)").Where("region = ?", "west")
+	a.With("recent", recent)
+
+	sql, args := pgsql.Build(a)
+	assert.Equal(t, "with recent as (select id from orders where (created_at > $1)) select * from customers where (id in (select id from recent)) and (region = $2)", sql)
+	assert.Equal(t, []interface{}{"2022-01-01", "west"}, args)
+}
+
+func TestSelectStatementWithRecursive(t *testing.T) {
+	a := pgsql.Select("n").From("t")
+	a.WithCTE(&pgsql.CommonTableExpression{
+		Name:      "t",
+		Columns:   []string{"n"},
+		Recursive: true,
+		Query:     pgsql.Select("1").Select("n+1"),
+	})
+	a.With("u", pgsql.Select("?::int", 7))
+
+	sql, args := pgsql.Build(a)
+	assert.Equal(t, "with recursive t(n) as (select 1, n+1), u as (select $1::int) select n from t", sql)
+	assert.Equal(t, []interface{}{7}, args)
+
+	b := pgsql.Select("*").From("t").WithRecursive("t", pgsql.Select("1"))
+	sql, args = pgsql.Build(b)
+	assert.Equal(t, "with recursive t as (select 1) select * from t", sql)
+	assert.Empty(t, args)
+}
+
+func TestCommonTableExpressionMaterialized(t *testing.T) {
+	a := pgsql.Select("*").From("a, b")
+	a.WithCTE(&pgsql.CommonTableExpression{Name: "a", Materialized: pgsql.Materialized, Query: pgsql.Select("1")})
+	a.WithCTE(&pgsql.CommonTableExpression{Name: "b", Materialized: pgsql.NotMaterialized, Query: pgsql.Select("2")})
+
+	sql, args := pgsql.Build(a)
+	assert.Equal(t, "with a as materialized (select 1), b as not materialized (select 2) select * from a, b", sql)
+	assert.Empty(t, args)
+}
+
+func TestSelectStatementApplyWith(t *testing.T) {
+	a := pgsql.Select("*").From("people")
+	b := pgsql.Where("team_id in (select id from active_teams)").
+		With("active_teams", pgsql.Select("id").From("teams").Where("active = ?", true))
+	a.Apply(b)
+
+	sql, args := pgsql.Build(a)
+	assert.Equal(t, "with active_teams as (select id from teams where (active = $1)) select * from people where (team_id in (select id from active_teams))", sql)
+	assert.Equal(t, []interface{}{true}, args)
+}
+
+func TestInsertStatementWith(t *testing.T) {
+	a := pgsql.Insert("archive").With("old", pgsql.Select("*").From("people").Where("age > ?", 90))
+	a.Columns("name")
+	a.Values(pgsql.Values().Row("Alice"))
+
+	sql, args := pgsql.Build(a)
+	assert.Equal(t, "with old as (select * from people where (age > $1)) insert into archive (name) values ($2)", sql)
+	assert.Equal(t, []interface{}{90, "Alice"}, args)
+}
+
+func TestUpdateStatementWith(t *testing.T) {
+	a := pgsql.Update("people").With("t", pgsql.Select("id").From("teams").Where("name = ?", "red"))
+	a.Setf("active = ?", false)
+	a.Where("team_id in (select id from t)")
+
+	sql, args := pgsql.Build(a)
+	assert.Equal(t, "with t as (select id from teams where (name = $1)) update people set active = $2 where (team_id in (select id from t))", sql)
+	assert.Equal(t, []interface{}{"red", false}, args)
+}
+
+func TestDeleteStatementWith(t *testing.T) {
+	a := pgsql.Delete("people").With("t", pgsql.Select("id").From("teams").Where("name = ?", "red"))
+	a.Where("team_id in (select id from t)")
+	a.Apply(pgsql.Where("age > ?", 30).With("u", pgsql.Select("1")))
+
+	sql, args := pgsql.Build(a)
+	assert.Equal(t, "with t as (select id from teams where (name = $1)), u as (select 1) delete from people where (team_id in (select id from t)) and (age > $2)", sql)
+	assert.Equal(t, []interface{}{"red", 30}, args)
+}